@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/vault/api"
 	auth "github.com/hashicorp/vault/api/auth/approle"
 	"github.com/skit-ai/vcore/env"
+	"github.com/skit-ai/vcore/errors"
 )
 
 // Read Env Vars
@@ -26,6 +27,19 @@ var static_data_key string = env.String("STATIC_DATA_KEY", "")
 
 // Other Global Variables
 
+// vaultRetryPolicy does not retry the requests rejected by vault(4xx) since they would be rejected again
+var vaultRetryPolicy = func() errors.RetryPolicy {
+	policy := errors.DefaultRetryPolicy
+	policy.Classifier = func(err error) bool {
+		var responseErr *api.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode >= 400 && responseErr.StatusCode < 500 {
+			return false
+		}
+		return errors.Retryable(err)
+	}
+	return policy
+}()
+
 var data_key []byte
 var dataKeyCache map[string][]byte = map[string][]byte{}
 
@@ -88,7 +102,11 @@ func getDataKey(encrypted_data_key_ string, clientId string) (data_key_ []byte)
 
 	// Initialize approle auth
 	appRoleAuth := getApproleAuth()
-	secret_, err := client.Auth().Login(context.TODO(), appRoleAuth)
+	var secret_ *api.Secret
+	err = errors.Retry(context.TODO(), vaultRetryPolicy, func(ctx context.Context) (err error) {
+		secret_, err = client.Auth().Login(ctx, appRoleAuth)
+		return
+	})
 	if err != nil {
 		return nil
 	}
@@ -122,7 +140,11 @@ func getDataKey(encrypted_data_key_ string, clientId string) (data_key_ []byte)
 	data := map[string]interface{}{
 		"ciphertext": ciphertext,
 	}
-	secret, err := client.Logical().Write("/transit/decrypt/"+vaultDataKeyName_, data)
+	var secret *api.Secret
+	err = errors.Retry(context.TODO(), vaultRetryPolicy, func(ctx context.Context) (err error) {
+		secret, err = client.Logical().WriteWithContext(ctx, "/transit/decrypt/"+vaultDataKeyName_, data)
+		return
+	})
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,94 @@
+package errors
+
+// annotated is a layer which only adds tags and extras to an error.
+// Unlike a rung it does not have a say on whether the error is fatal, to be
+// ignored or its code, so the ones of the wrapped error are reported as is.
+// It does not add a stack either, the stack of the wrapped error is kept.
+type annotated struct {
+	cause  error
+	tags   map[string]string
+	extras map[string]interface{}
+}
+
+func (e *annotated) Error() string {
+	return e.cause.Error()
+}
+
+func (e *annotated) Cause() error {
+	return e.cause
+}
+
+func (e *annotated) Unwrap() error {
+	return e.cause
+}
+
+func (e *annotated) Tags() map[string]string {
+	return e.tags
+}
+
+func (e *annotated) Extras() map[string]interface{} {
+	return e.extras
+}
+
+// annotate wraps an error in a layer carrying the given tags and extras.
+// The maps are not copied, callers are expected to pass maps they own.
+func annotate(err error, tags map[string]string, extras map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &annotated{
+		cause:  err,
+		tags:   tags,
+		extras: extras,
+	}
+}
+
+// AttachTags returns a new error carrying the given tags in addition to the
+// ones of err. The tags override the tags with the same keys already present
+// anywhere in the chain. err itself is left untouched along with its stack,
+// fatality and code, so it is safe to tag the same error concurrently.
+// The map is copied. nil is returned if err is nil.
+func AttachTags(err error, tags map[string]string) error {
+	if err == nil || len(tags) == 0 {
+		return err
+	}
+
+	top, cause := topAnnotation(err)
+	merged := make(map[string]string, len(top.tags)+len(tags))
+	for k, v := range top.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return annotate(cause, merged, top.extras)
+}
+
+// AttachExtras returns a new error carrying the given extras in addition to
+// the ones of err. It follows the semantics of AttachTags.
+func AttachExtras(err error, extras map[string]interface{}) error {
+	if err == nil || len(extras) == 0 {
+		return err
+	}
+
+	top, cause := topAnnotation(err)
+	merged := make(map[string]interface{}, len(top.extras)+len(extras))
+	for k, v := range top.extras {
+		merged[k] = v
+	}
+	for k, v := range extras {
+		merged[k] = v
+	}
+	return annotate(cause, top.tags, merged)
+}
+
+// topAnnotation returns the annotation layer at the top of err along with the
+// error it wraps, so that annotating an error repeatedly does not keep adding
+// layers. An empty annotation and err are returned if err is not annotated.
+// The maps of the annotation returned must not be modified.
+func topAnnotation(err error) (*annotated, error) {
+	if top, ok := err.(*annotated); ok {
+		return top, top.cause
+	}
+	return &annotated{}, err
+}
@@ -0,0 +1,123 @@
+package errors
+
+import "context"
+
+// contextKey is the key of the metadata stored on a context by ContextWithTags and ContextWithExtras
+type contextKey struct{}
+
+// contextMetadata is the metadata stored on a context. It is never modified
+// once stored, deriving a context copies it.
+type contextMetadata struct {
+	tags   map[string]string
+	extras map[string]interface{}
+}
+
+func metadataFromContext(ctx context.Context) contextMetadata {
+	if ctx == nil {
+		return contextMetadata{}
+	}
+	metadata, _ := ctx.Value(contextKey{}).(contextMetadata)
+	return metadata
+}
+
+// ContextWithTags returns a context carrying the given tags in addition to the
+// ones already carried by ctx, for eg. the identifiers of the call being handled:
+//
+//	ctx = errors.ContextWithTags(ctx, map[string]string{"call_uuid": callUUID, "flow_uuid": flowUUID})
+//
+// FromContext adds them to errors. The map is copied.
+func ContextWithTags(ctx context.Context, tags map[string]string) context.Context {
+	metadata := metadataFromContext(ctx)
+	merged := make(map[string]string, len(metadata.tags)+len(tags))
+	for k, v := range metadata.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	metadata.tags = merged
+	return context.WithValue(ctx, contextKey{}, metadata)
+}
+
+// ContextWithExtras returns a context carrying the given extras in addition to
+// the ones already carried by ctx. The map is copied.
+func ContextWithExtras(ctx context.Context, extras map[string]interface{}) context.Context {
+	metadata := metadataFromContext(ctx)
+	merged := make(map[string]interface{}, len(metadata.extras)+len(extras))
+	for k, v := range metadata.extras {
+		merged[k] = v
+	}
+	for k, v := range extras {
+		merged[k] = v
+	}
+	metadata.extras = merged
+	return context.WithValue(ctx, contextKey{}, metadata)
+}
+
+// TagsFromContext returns a copy of the tags carried by the context, nil if there are none
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags := metadataFromContext(ctx).tags
+	if len(tags) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(tags))
+	for k, v := range tags {
+		copied[k] = v
+	}
+	return copied
+}
+
+// ExtrasFromContext returns a copy of the extras carried by the context, nil if there are none
+func ExtrasFromContext(ctx context.Context) map[string]interface{} {
+	extras := metadataFromContext(ctx).extras
+	if len(extras) == 0 {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(extras))
+	for k, v := range extras {
+		copied[k] = v
+	}
+	return copied
+}
+
+// FromContext returns err with the tags and extras carried by the context.
+// They have the lowest priority, i.e. the tags and extras already present in
+// the chain of err are never overridden. Like AttachTags, err is left untouched.
+// nil is returned if err is nil.
+func FromContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	metadata := metadataFromContext(ctx)
+
+	var tags map[string]string
+	if len(metadata.tags) > 0 {
+		existing := Tags(err)
+		for k, v := range metadata.tags {
+			if _, ok := existing[k]; ok {
+				continue
+			}
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[k] = v
+		}
+	}
+
+	var extras map[string]interface{}
+	if len(metadata.extras) > 0 {
+		existing := Extras(err)
+		for k, v := range metadata.extras {
+			if _, ok := existing[k]; ok {
+				continue
+			}
+			if extras == nil {
+				extras = make(map[string]interface{})
+			}
+			extras[k] = v
+		}
+	}
+
+	return AttachExtras(AttachTags(err, tags), extras)
+}
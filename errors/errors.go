@@ -1,9 +1,11 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	_err "github.com/pkg/errors"
 )
@@ -19,12 +21,14 @@ import (
 // If the error does not implement Fatal, false will be returned.
 // If the error is nil, false will be returned without further investigation.
 // The logic will loop through the topmost error of the stack followed by all
-// it's causes provided it implements the causer interface:
+// it's causes provided it implements the causer interface or the unwrap
+// interfaces of the standard library (see walk):
 //
 //	  type causer interface {
 //			  Cause() error
 //	  }
 // If any one of the causes is fatal, the error is deemed fatal. i.e. irrecoverable
+// When an error wraps multiple errors, it is fatal if any of the wrapped chains is fatal.
 func Fatal(err error) (isFatal bool) {
 	type fatality interface {
 		Fatal() bool
@@ -32,28 +36,31 @@ func Fatal(err error) (isFatal bool) {
 
 	// Keep going through all the errors in the stack until we hit one error which implements fatality
 	// We use this first error to check if the error is fatal or not.
-	for err != nil {
+	walk(err, func(err error) bool {
 		if check, ok := err.(fatality); ok {
 			isFatal = check.Fatal()
-			break
+			return false
 		}
 
-		// Going to the cause of the current error(if any)
-		cause, ok := err.(causer)
-		if !ok {
-			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
-			// in the first place. Hence breaking the loop.
-			break
+		if multi, ok := err.(multiWrapper); ok {
+			// Each of the wrapped errors is a chain of its own. The error is fatal if any one of them is.
+			for _, child := range multi.Unwrap() {
+				if Fatal(child) {
+					isFatal = true
+					break
+				}
+			}
+			return false
 		}
-
-		err = cause.Cause()
-	}
+		return true
+	})
 
 	return
 }
 
 // Custom error that implements:
 // - cause interface from github.com/pkg/errors
+// - unwrap interface from the go standard library
 // - error interface from go builtin
 // - fatality interface from FSM
 // It represents a rung in the chain of errors leading to the cause.
@@ -65,6 +72,10 @@ type rung struct {
 	extras map[string]interface{}
 	ignore bool
 	code   int
+	kind   Kind
+
+	retryAfter time.Duration
+	violations []FieldViolation
 }
 
 func (e *rung) Error() (errorMsg string) {
@@ -83,6 +94,12 @@ func (e *rung) Cause() error {
 	return e.cause
 }
 
+// Implementing the unwrap interface so that errors.Is and errors.As from the
+// standard library can look past a rung
+func (e *rung) Unwrap() error {
+	return e.cause
+}
+
 func (e *rung) Fatal() bool {
 	return e.fatal
 }
@@ -99,70 +116,60 @@ func (e *rung) Ignore() bool {
 	return e.ignore
 }
 
+// Code returns the code set on the rung. If no code was set, the HTTP status
+// code of the rung's kind is returned.
 func (e *rung) Code() int {
+	if e.code == 0 {
+		return e.kind.Code()
+	}
 	return e.code
 }
 
+func (e *rung) Kind() Kind {
+	return e.kind
+}
+
+func (e *rung) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *rung) FieldViolations() []FieldViolation {
+	return e.violations
+}
+
+// Is allows errors.Is to match a rung against the Kind it belongs to
+func (e *rung) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind != Unknown && kind == e.kind
+}
+
 // Creates an error which is chained with a cause
 func NewError(_msg string, _cause error, _fatal bool) error {
-	return NewErrorWithTags(_msg, _cause, _fatal, nil)
+	return newError(_msg, WithCause(_cause), WithFatal(_fatal))
 }
 
 // Creates an error which is chained with a cause
 func NewErrorWithTags(_msg string, _cause error, _fatal bool, _tags map[string]string) error {
-	err := &rung{
-		cause: _cause,
-		msg:   _msg,
-		fatal: _fatal,
-		tags:  _tags,
-	}
-	return _err.WithStack(err)
+	return newError(_msg, WithCause(_cause), WithFatal(_fatal), WithTags(_tags))
 }
 
 // Creates an error which is chained with a cause
 func NewErrorWithExtras(_msg string, _cause error, _fatal bool, _extras map[string]interface{}) error {
-	err := &rung{
-		cause:  _cause,
-		msg:    _msg,
-		fatal:  _fatal,
-		tags:   nil,
-		extras: _extras,
-	}
-	return _err.WithStack(err)
+	return newError(_msg, WithCause(_cause), WithFatal(_fatal), WithExtras(_extras))
 }
 
 func NewErrorWithTagsAndExtras(_msg string, _cause error, _fatal bool, _tags map[string]string, _extras map[string]interface{}) error {
-	err := &rung{
-		cause:  _cause,
-		msg:    _msg,
-		fatal:  _fatal,
-		tags:   _tags,
-		extras: _extras,
-	}
-	return _err.WithStack(err)
+	return newError(_msg, WithCause(_cause), WithFatal(_fatal), WithTags(_tags), WithExtras(_extras))
 }
 
 // NewErrorToIgnore returns an error that informs loggers to ignore it
 func NewErrorToIgnore(_msg string, _cause error) error {
-	err := &rung{
-		cause:  _cause,
-		msg:    _msg,
-		fatal:  false,
-		ignore: true,
-	}
-	return _err.WithStack(err)
+	return newError(_msg, WithCause(_cause), Ignored())
 }
 
 // NewErrorWithCode returns an error that has an int code associated with it
 func NewErrorWithCode(_msg string, code int, _cause error) error {
-	err := &rung{
-		cause:  _cause,
-		msg:    _msg,
-		fatal:  false,
-		ignore: false,
-		code:   code,
-	}
-	return _err.WithStack(err)
+	return newError(_msg, WithCause(_cause), WithCode(code))
 }
 
 // Based on https://godoc.org/github.com/pkg/errors#hdr-Formatted_printing_of_errors
@@ -170,60 +177,18 @@ type stackTracer interface {
 	StackTrace() _err.StackTrace
 }
 
-// AddTagsToError checks if the input error implements a causer interface. If it does,
-// it checks if the Cause is of internal type *rung. When this check also passes,
-// it adds the input _tags to the existing tags information. In case the checks fail,
-// the original error is returned.
+// AddTagsToError adds tags to an error without modifying it.
+//
+// Deprecated: use AttachTags, which this delegates to.
 func AddTagsToError(err error, _tags map[string]string) error {
-	errCauser, ok := err.(causer)
-	if !ok || errCauser == nil {
-		return err
-	}
-
-	errAsRung, ok := errCauser.Cause().(*rung)
-	if !ok || errAsRung == nil {
-		return err
-	}
-
-	existingTags := errAsRung.Tags()
-	if existingTags == nil {
-		existingTags = make(map[string]string)
-	}
-
-	for key, value := range _tags {
-		existingTags[key] = value
-	}
-
-	errAsRung.tags = existingTags
-	return errAsRung
+	return AttachTags(err, _tags)
 }
 
-// AddExtrasToError checks if the input error implements a causer interface. If it does,
-// it checks if the Cause is of internal type *rung. When this check also passes,
-// it adds the input _extras to the existing extras information. In case the checks fail,
-// the original error is returned.
+// AddExtrasToError adds extras to an error without modifying it.
+//
+// Deprecated: use AttachExtras, which this delegates to.
 func AddExtrasToError(err error, _extras map[string]interface{}) error {
-	errCauser, ok := err.(causer)
-	if !ok || errCauser == nil {
-		return err
-	}
-
-	errAsRung, ok := errCauser.Cause().(*rung)
-	if !ok || errAsRung == nil {
-		return err
-	}
-
-	existingExtras := errAsRung.Extras()
-	if existingExtras == nil {
-		existingExtras = make(map[string]interface{})
-	}
-
-	for key, value := range _extras {
-		existingExtras[key] = value
-	}
-
-	errAsRung.extras = existingExtras
-	return errAsRung
+	return AttachExtras(err, _extras)
 }
 
 // Determines the stacktrace of an error.
@@ -233,25 +198,9 @@ func Stacktrace(err error) string {
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("%v\n", err))
 
-	// Find the deepest element in the stack which implements the stackTracer interface
-	var deepestStacktracer stackTracer
-	for err != nil {
-		if val, ok := err.(stackTracer); ok {
-			deepestStacktracer = val
-		}
-
-		cause, ok := err.(causer)
-		if !ok {
-			break
-		}
-		err = cause.Cause()
-	}
-
 	// Printing the entire stacktrace starting from the original cause of this issue
-	if deepestStacktracer != nil {
-		for _, f := range deepestStacktracer.StackTrace() {
-			builder.WriteString(fmt.Sprintf("%+s:%d\n", f, f))
-		}
+	for _, f := range Frames(err) {
+		builder.WriteString(fmt.Sprintf("%s\n\t%s:%d\n", f.Function, f.File, f.Line))
 	}
 	return builder.String()
 }
@@ -270,13 +219,58 @@ type causer interface {
 	Cause() error
 }
 
+// The interfaces used by the standard library to expose wrapped errors.
+// wrapper is implemented by errors created with fmt.Errorf("%w") and
+// multiWrapper by errors created with errors.Join or fmt.Errorf with
+// multiple %w verbs.
+type wrapper interface {
+	Unwrap() error
+}
+
+type multiWrapper interface {
+	Unwrap() []error
+}
+
+// walk visits err followed by every error in its chain, depth first.
+// The next error in the chain is found through the causer interface and,
+// failing that, through the standard library's unwrap interfaces. Errors
+// exposing several wrapped errors have each of them visited in order.
+// The walk stops as soon as visit returns false, in which case walk
+// also returns false.
+func walk(err error, visit func(error) bool) bool {
+	for err != nil {
+		if !visit(err) {
+			return false
+		}
+
+		switch e := err.(type) {
+		case causer:
+			err = e.Cause()
+		case wrapper:
+			err = e.Unwrap()
+		case multiWrapper:
+			for _, child := range e.Unwrap() {
+				if !walk(child, visit) {
+					return false
+				}
+			}
+			return true
+		default:
+			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
+			// in the first place. Hence stopping the walk.
+			return true
+		}
+	}
+	return true
+}
+
 func Tags(err error) (cumulativeTags map[string]string) {
 	type tagged interface {
 		Tags() map[string]string
 	}
 
 	// Keep going through all the errors in the stack and make a cumulative map of all the tags
-	for err != nil {
+	walk(err, func(err error) bool {
 		if check, ok := err.(tagged); ok {
 			tagsSet := check.Tags()
 			if tagsSet != nil {
@@ -291,17 +285,8 @@ func Tags(err error) (cumulativeTags map[string]string) {
 				}
 			}
 		}
-
-		// Going to the cause of the current error(if any)
-		cause, ok := err.(causer)
-		if !ok {
-			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
-			// in the first place. Hence breaking the loop.
-			break
-		}
-
-		err = cause.Cause()
-	}
+		return true
+	})
 
 	return
 }
@@ -312,7 +297,7 @@ func Extras(err error) (cumulativeExtras map[string]interface{}) {
 	}
 
 	// Keep going through all the errors in the stack and make a cumulative map of all the tags
-	for err != nil {
+	walk(err, func(err error) bool {
 		if check, ok := err.(extra); ok {
 			extrasSet := check.Extras()
 			if extrasSet != nil {
@@ -327,18 +312,32 @@ func Extras(err error) (cumulativeExtras map[string]interface{}) {
 				}
 			}
 		}
+		return true
+	})
 
-		// Going to the cause of the current error(if any)
-		cause, ok := err.(causer)
-		if !ok {
-			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
-			// in the first place. Hence breaking the loop.
-			break
-		}
+	return
+}
+
+// FieldViolation describes a single field of a request which failed validation
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
 
-		err = cause.Cause()
+// FieldViolations returns the field violations of all the errors in the stack,
+// starting from the topmost error.
+func FieldViolations(err error) (violations []FieldViolation) {
+	type violated interface {
+		FieldViolations() []FieldViolation
 	}
 
+	walk(err, func(err error) bool {
+		if check, ok := err.(violated); ok {
+			violations = append(violations, check.FieldViolations()...)
+		}
+		return true
+	})
+
 	return
 }
 
@@ -348,47 +347,77 @@ func Ignore(err error) bool {
 	}
 
 	// Keep going through all the errors in the stack and find if any error is supposed to be ignored
-	for err != nil {
-		if check, ok := err.(ignore); ok {
-			ignore := check.Ignore()
-			if ignore {
-				return true
-			}
+	var ignored bool
+	walk(err, func(err error) bool {
+		check, ok := err.(ignore)
+		if ok && check.Ignore() {
+			ignored = true
+			return false
 		}
 
-		// Going to the cause of the current error(if any)
-		cause, ok := err.(causer)
-		if !ok {
-			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
-			// in the first place. Hence breaking the loop.
-			break
+		if multi, isMulti := err.(multiWrapper); isMulti {
+			// An error wrapping multiple errors which implements ignore(for eg. Multi) decides for all of them.
+			// Otherwise it is ignored if any of the wrapped chains is.
+			if !ok {
+				for _, child := range multi.Unwrap() {
+					if ignored = Ignore(child); ignored {
+						break
+					}
+				}
+			}
+			return false
 		}
+		return true
+	})
 
-		err = cause.Cause()
-	}
-
-	return false
+	return ignored
 }
 
-// Finds the deepest non-nil cause
+// Finds the deepest non-nil cause.
+// The chain is followed through both the causer and the standard library's
+// single error unwrap interface. An error wrapping several errors is treated
+// as the deepest cause since there is no single error beneath it.
 func DeepestCause(err error) error {
-	var cause causer
-	var ok bool
 	for err != nil {
-		if cause, ok = err.(causer); !ok {
+		var next error
+		switch e := err.(type) {
+		case causer:
+			next = e.Cause()
+		case wrapper:
+			next = e.Unwrap()
+		}
+
+		if next == nil {
 			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
 			// in the first place. Hence breaking the loop.
-			return err
-		}
-		if cause.Cause() != nil {
-			err = cause.Cause()
-		} else {
 			break
 		}
+		err = next
 	}
 	return err
 }
 
+// codeOf returns the first non-zero code in the stack, 0 if there is none
+func codeOf(err error) (code int) {
+	type errorCode interface {
+		Code() int
+	}
+
+	// Keep going through all the errors in the stack until we hit one error
+	// which implements errorCode and has a non-zero error code.
+	// We use this first error to return the error code.
+	walk(err, func(err error) bool {
+		if check, ok := err.(errorCode); ok {
+			if code = check.Code(); code != 0 {
+				return false
+			}
+		}
+		return true
+	})
+
+	return
+}
+
 // This allows us to attach a response code with an error. This is achieved by implementing
 // the errorCode interface:
 //     type errorCode interface {
@@ -398,7 +427,8 @@ func DeepestCause(err error) error {
 // If the error does not implement errorCode, 500 will be returned.
 // If the error is nil, 200 will be returned without further investigation.
 // The logic will loop through the topmost error of the stack followed by all
-// it's causes provided it implements the causer interface:
+// it's causes provided it implements the causer interface or the unwrap
+// interfaces of the standard library (see walk):
 //
 //	  type causer interface {
 //			  Cause() error
@@ -411,32 +441,7 @@ func Code(err error, defaultCode int) (code int) {
 		return http.StatusOK
 	}
 
-	type errorCode interface {
-		Code() int
-	}
-
-	// Keep going through all the errors in the stack until we hit one error
-	// which implements errorCode and has a non-zero error code.
-	// We use this first error to return the error code.
-	for err != nil {
-		if check, ok := err.(errorCode); ok {
-			if code = check.Code(); code != 0 {
-				break
-			}
-		}
-
-		// Going to the cause of the current error(if any)
-		cause, ok := err.(causer)
-		if !ok {
-			// Since there is no cause of the current error, it is the root error(original error) that caused the issue
-			// in the first place. Hence breaking the loop.
-			break
-		}
-
-		err = cause.Cause()
-	}
-
-	if code <= 0 {
+	if code = codeOf(err); code <= 0 {
 		if defaultCode <= 0 {
 			code = http.StatusInternalServerError
 		} else {
@@ -445,3 +450,30 @@ func Code(err error, defaultCode int) (code int) {
 	}
 	return
 }
+
+// Is reports whether any error in err's chain matches target.
+// It is a wrapper over errors.Is from the standard library so that callers
+// of this package do not need to import both.
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, and if one is
+// found, sets target to that error value and returns true.
+// It is a wrapper over errors.As from the standard library.
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, if err's
+// type contains an Unwrap method returning error. Otherwise, Unwrap returns nil.
+// It is a wrapper over errors.Unwrap from the standard library.
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
+
+// Join returns an error that wraps the given errors.
+// It is a wrapper over errors.Join from the standard library.
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
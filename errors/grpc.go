@@ -0,0 +1,291 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// GRPCErrorDomain is the domain set on the ErrorInfo detail of the statuses
+// created by ToGRPCStatus. FromGRPCStatus only trusts the metadata of
+// ErrorInfo details belonging to this domain.
+var GRPCErrorDomain = "vcore"
+
+// Keys of the ErrorInfo metadata used to carry the fields of an error
+const (
+	grpcMetaCode        = "code"
+	grpcMetaFatal       = "fatal"
+	grpcMetaIgnore      = "ignore"
+	grpcMetaTagPrefix   = "tag."
+	grpcMetaExtraPrefix = "extra."
+)
+
+// ToGRPCStatus converts an error into a gRPC status.
+// The status code is derived from the kind of the error(see GRPCCode) and the
+// message is the message of the topmost error of the stack, like the detail
+// written by ProblemResponder, since the messages of the causes usually describe
+// internals. The code, fatal and ignore flags, tags and extras of the error are
+// carried in an ErrorInfo detail, the retry after duration in a RetryInfo detail
+// and the field violations in a BadRequest detail.
+// If any error of the chain is a gRPC status error, its status is returned as is.
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	var se interface{ GRPCStatus() *status.Status }
+	if As(err, &se) {
+		return se.GRPCStatus()
+	}
+
+	code := GRPCCode(err)
+	msg := publicMessage(err)
+	if msg == "" {
+		msg = code.String()
+	}
+	st := status.New(code, msg)
+
+	metadata := map[string]string{
+		grpcMetaCode:   strconv.Itoa(Code(err, 0)),
+		grpcMetaFatal:  strconv.FormatBool(Fatal(err)),
+		grpcMetaIgnore: strconv.FormatBool(Ignore(err)),
+	}
+	for k, v := range Tags(err) {
+		metadata[grpcMetaTagPrefix+k] = v
+	}
+	for k, v := range Extras(err) {
+		// Extras which cannot be encoded are dropped since they cannot be rebuilt on the other side
+		if encoded, jsonErr := json.Marshal(v); jsonErr == nil {
+			metadata[grpcMetaExtraPrefix+k] = string(encoded)
+		}
+	}
+
+	reason := KindOf(err)
+	details := []proto.Message{&errdetails.ErrorInfo{
+		Reason:   strings.ToUpper(reason.Error()),
+		Domain:   GRPCErrorDomain,
+		Metadata: metadata,
+	}}
+
+	if d := RetryAfter(err); d > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+
+	if violations := FieldViolations(err); len(violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+	return st
+}
+
+// FromGRPCStatus rebuilds an error from a gRPC status.
+// It is the counterpart of ToGRPCStatus, the fields carried by the details of
+// the status are set on the error. The returned error still implements
+// GRPCStatus() so that status.FromError and status.Code keep working on it.
+// nil is returned for a nil or OK status.
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	opts := []Option{WithKind(kindFromGRPC(st.Code()))}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() != GRPCErrorDomain {
+				continue
+			}
+			if kind := Kind(strings.ToLower(d.GetReason())); kind.Code() != 0 {
+				opts = append(opts, WithKind(kind))
+			}
+			opts = append(opts, grpcMetadataOptions(d.GetMetadata())...)
+		case *errdetails.RetryInfo:
+			opts = append(opts, WithRetryAfter(d.GetRetryDelay().AsDuration()))
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				opts = append(opts, WithFieldViolation(v.GetField(), v.GetDescription()))
+			}
+		}
+	}
+
+	return &statusError{
+		cause:  New(st.Message(), opts...),
+		status: st,
+	}
+}
+
+// grpcMetadataOptions converts the metadata of an ErrorInfo detail into options
+func grpcMetadataOptions(metadata map[string]string) (opts []Option) {
+	tags := make(map[string]string)
+	extras := make(map[string]interface{})
+	for k, v := range metadata {
+		switch {
+		case k == grpcMetaCode:
+			if code, err := strconv.Atoi(v); err == nil {
+				opts = append(opts, WithCode(code))
+			}
+		case k == grpcMetaFatal:
+			fatal, _ := strconv.ParseBool(v)
+			opts = append(opts, WithFatal(fatal))
+		case k == grpcMetaIgnore:
+			if ignore, _ := strconv.ParseBool(v); ignore {
+				opts = append(opts, Ignored())
+			}
+		case strings.HasPrefix(k, grpcMetaTagPrefix):
+			tags[strings.TrimPrefix(k, grpcMetaTagPrefix)] = v
+		case strings.HasPrefix(k, grpcMetaExtraPrefix):
+			var extra interface{}
+			if err := json.Unmarshal([]byte(v), &extra); err != nil {
+				extra = v
+			}
+			extras[strings.TrimPrefix(k, grpcMetaExtraPrefix)] = extra
+		}
+	}
+	return append(opts, WithTags(tags), WithExtras(extras))
+}
+
+// kindFromGRPC maps a gRPC status code to a Kind
+func kindFromGRPC(code codes.Code) Kind {
+	for kind, c := range kindCodes {
+		if c.grpc == code {
+			return kind
+		}
+	}
+	return Unknown
+}
+
+// statusError is the error returned by FromGRPCStatus.
+// It keeps the status it was built from so that it can be converted back without any loss.
+type statusError struct {
+	cause  error
+	status *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *statusError) Cause() error {
+	return e.cause
+}
+
+func (e *statusError) Unwrap() error {
+	return e.cause
+}
+
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// UnaryServerInterceptor returns a grpc interceptor that converts the errors
+// returned by handlers into gRPC statuses using ToGRPCStatus.
+// When used with other interceptors that inspect errors(for eg. the Sentry
+// interceptor), it should be the outermost one so that the others see the
+// original error.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			err = ToGRPCStatus(err).Err()
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc interceptor that converts the errors
+// returned by stream handlers into gRPC statuses using ToGRPCStatus.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := handler(srv, stream); err != nil {
+			return ToGRPCStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// UnaryClientInterceptor returns a grpc interceptor that rebuilds errors from
+// the gRPC statuses returned by the server using FromGRPCStatus.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return fromGRPCError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a grpc interceptor that rebuilds errors from
+// the gRPC statuses returned by the server using FromGRPCStatus.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromGRPCError(err)
+		}
+		return &clientStream{stream}, nil
+	}
+}
+
+// clientStream converts the errors received on a client stream
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return fromGRPCError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return fromGRPCError(s.ClientStream.RecvMsg(m))
+}
+
+// fromGRPCError rebuilds an error from a gRPC status error.
+// Errors which are not status errors(for eg. io.EOF) are returned as is.
+func fromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return FromGRPCStatus(st)
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Kind is the category of an error.
+// A Kind is an error in itself so that it can be compared using errors.Is:
+//
+//	if errors.Is(err, errors.NotFound) {
+//		...
+//	}
+//
+// Every Kind maps to a default HTTP status code, which is returned by Code()
+// when no explicit code is set on the error, and to a gRPC status code.
+type Kind string
+
+const (
+	// Unknown is the zero value of Kind. It is used when an error has not been categorised.
+	Unknown          Kind = ""
+	InvalidArgument  Kind = "invalid_argument"
+	Unauthorized     Kind = "unauthorized"
+	PermissionDenied Kind = "permission_denied"
+	NotFound         Kind = "not_found"
+	Conflict         Kind = "conflict"
+	RateLimited      Kind = "rate_limited"
+	Canceled         Kind = "canceled"
+	Timeout          Kind = "timeout"
+	Unavailable      Kind = "unavailable"
+	Unimplemented    Kind = "unimplemented"
+	Internal         Kind = "internal"
+)
+
+// statusClientClosedRequest is the de-facto status code for a request closed by the client
+const statusClientClosedRequest = 499
+
+// kindCodes holds the HTTP and gRPC status codes each Kind maps to
+var kindCodes = map[Kind]struct {
+	http int
+	grpc codes.Code
+}{
+	InvalidArgument:  {http.StatusBadRequest, codes.InvalidArgument},
+	Unauthorized:     {http.StatusUnauthorized, codes.Unauthenticated},
+	PermissionDenied: {http.StatusForbidden, codes.PermissionDenied},
+	NotFound:         {http.StatusNotFound, codes.NotFound},
+	Conflict:         {http.StatusConflict, codes.AlreadyExists},
+	RateLimited:      {http.StatusTooManyRequests, codes.ResourceExhausted},
+	Canceled:         {statusClientClosedRequest, codes.Canceled},
+	Timeout:          {http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	Unavailable:      {http.StatusServiceUnavailable, codes.Unavailable},
+	Unimplemented:    {http.StatusNotImplemented, codes.Unimplemented},
+	Internal:         {http.StatusInternalServerError, codes.Internal},
+}
+
+func (k Kind) Error() string {
+	if k == Unknown {
+		return "unknown"
+	}
+	return string(k)
+}
+
+// Code returns the HTTP status code of the kind.
+// 0 is returned for Unknown so that Code() can fall back to its default.
+func (k Kind) Code() int {
+	return kindCodes[k].http
+}
+
+// GRPCCode returns the gRPC status code of the kind.
+func (k Kind) GRPCCode() codes.Code {
+	if c, ok := kindCodes[k]; ok {
+		return c.grpc
+	}
+	return codes.Unknown
+}
+
+// NewErrorWithKind returns an error that belongs to the given kind.
+// Unless a code is set on the chain explicitly, Code() of the error will be
+// the HTTP status code of the kind.
+func NewErrorWithKind(_msg string, kind Kind, _cause error) error {
+	return newError(_msg, WithCause(_cause), WithKind(kind))
+}
+
+// KindOf returns the kind of an error.
+// The logic will loop through the topmost error of the stack followed by all
+// it's causes and returns the first kind found. A Kind present in the chain
+// as an error(for eg. fmt.Errorf("...: %w", errors.NotFound)) counts as well.
+// Unknown is returned if no kind is found.
+func KindOf(err error) (kind Kind) {
+	type kinded interface {
+		Kind() Kind
+	}
+
+	walk(err, func(err error) bool {
+		switch check := err.(type) {
+		case Kind:
+			kind = check
+		case kinded:
+			kind = check.Kind()
+		}
+		return kind == Unknown
+	})
+
+	return
+}
+
+// GRPCCode returns the gRPC status code of an error based on its kind.
+// If the error is nil, codes.OK is returned. If the error does not have a kind,
+// the code is derived from the HTTP status code returned by Code().
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	if kind := KindOf(err); kind != Unknown {
+		return kind.GRPCCode()
+	}
+
+	return grpcCodeFromHTTP(Code(err, 0))
+}
+
+// grpcCodeFromHTTP maps an HTTP status code to the closest gRPC status code
+func grpcCodeFromHTTP(code int) codes.Code {
+	for _, c := range kindCodes {
+		if c.http == code {
+			return c.grpc
+		}
+	}
+
+	switch {
+	case code >= 200 && code < 300:
+		return codes.OK
+	case code >= 400 && code < 500:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
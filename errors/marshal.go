@@ -0,0 +1,236 @@
+package errors
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the links of a serialized chain
+const (
+	linkRung       = "rung"
+	linkAnnotation = "annotation"
+	linkWrapper    = "wrapper"
+	linkError      = "error"
+	linkKind       = "kind"
+	linkMulti      = "multi"
+)
+
+// chainDocument is the JSON representation of an error
+type chainDocument struct {
+	Error string         `json:"error"`
+	Chain []linkDocument `json:"chain"`
+}
+
+// linkDocument is the JSON representation of a single error in a chain.
+// Type is one of:
+//   - rung: an error created by this package
+//   - annotation: tags and extras added to an error
+//   - wrapper: an error wrapping the next one in the chain whose message
+//     includes the message of the next one(for eg. fmt.Errorf("...: %w"))
+//   - error: an error which does not wrap another error
+//   - kind: a Kind present in the chain as an error
+//   - multi: an error wrapping multiple errors, each of which is a chain in Errors
+type linkDocument struct {
+	Type            string                 `json:"type"`
+	Message         string                 `json:"message,omitempty"`
+	Fatal           bool                   `json:"fatal,omitempty"`
+	Ignore          bool                   `json:"ignore,omitempty"`
+	Code            int                    `json:"code,omitempty"`
+	Kind            Kind                   `json:"kind,omitempty"`
+	RetryAfterMs    int64                  `json:"retry_after_ms,omitempty"`
+	Tags            map[string]string      `json:"tags,omitempty"`
+	Extras          map[string]interface{} `json:"extras,omitempty"`
+	FieldViolations []FieldViolation       `json:"field_violations,omitempty"`
+	Stack           []Frame                `json:"stack,omitempty"`
+	Errors          [][]linkDocument       `json:"errors,omitempty"`
+}
+
+// Marshal serializes an error and its entire chain into a JSON document:
+//
+//	{
+//	  "error": "<message of the error>",
+//	  "chain": [
+//	    {"type": "rung", "message": "...", "fatal": true, "code": 500, "tags": {...}, "extras": {...},
+//	     "stack": [{"function": "...", "file": "...", "line": 10}]},
+//	    {"type": "error", "message": "..."}
+//	  ]
+//	}
+//
+// The chain starts from the topmost error. The stack captured when an error
+// was created is set on the link of that error. Extras are serialized using
+// encoding/json, so they should be JSON friendly values.
+// Unmarshal rebuilds an equivalent error from the document.
+func Marshal(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(chainDocument{
+		Error: err.Error(),
+		Chain: marshalChain(err),
+	})
+}
+
+// Unmarshal rebuilds an error from a JSON document created by Marshal and
+// stores it in target. The rebuilt error reports the same message, fatal
+// flag, code, kind, tags, extras and stack frames as the original one.
+// target is set to nil for the "null" document.
+func Unmarshal(data []byte, target *error) error {
+	var document *chainDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	if document == nil {
+		*target = nil
+		return nil
+	}
+
+	*target = unmarshalChain(document.Chain)
+	return nil
+}
+
+// marshalChain converts a chain into its links, starting from the topmost error
+func marshalChain(err error) (links []linkDocument) {
+	// Stack captured by a layer without a document of its own. It belongs to the next link.
+	var pending []Frame
+
+	for err != nil {
+		link := linkDocument{Message: err.Error()}
+		var next error
+
+		switch e := err.(type) {
+		case *rung:
+			link = linkDocument{
+				Type:            linkRung,
+				Message:         e.msg,
+				Fatal:           e.fatal,
+				Ignore:          e.ignore,
+				Code:            e.code,
+				Kind:            e.kind,
+				RetryAfterMs:    e.retryAfter.Milliseconds(),
+				Tags:            e.tags,
+				Extras:          e.extras,
+				FieldViolations: e.violations,
+			}
+			next = e.cause
+		case *annotated:
+			link.Type, link.Message = linkAnnotation, ""
+			link.Tags, link.Extras = e.tags, e.extras
+			next = e.cause
+		case Kind:
+			link.Type, link.Message, link.Kind = linkKind, "", e
+		case multiWrapper:
+			link.Type = linkMulti
+			for _, child := range e.Unwrap() {
+				link.Errors = append(link.Errors, marshalChain(child))
+			}
+		default:
+			link.Type = linkError
+			switch c := err.(type) {
+			case causer:
+				next = c.Cause()
+			case wrapper:
+				next = c.Unwrap()
+			}
+			if next != nil {
+				link.Type = linkWrapper
+			}
+		}
+
+		// Layers only adding a stack(for eg. the ones created by pkg/errors.WithStack)
+		// are folded into the next link.
+		frames := ownFrames(err)
+		if link.Type == linkWrapper && next != nil && err.Error() == next.Error() {
+			if frames != nil {
+				pending = frames
+			}
+			err = next
+			continue
+		}
+
+		link.Stack = frames
+		if link.Stack == nil {
+			link.Stack = pending
+		}
+		pending = nil
+
+		links = append(links, link)
+		err = next
+	}
+
+	return
+}
+
+// unmarshalChain rebuilds a chain from its links, starting from the deepest error
+func unmarshalChain(links []linkDocument) (err error) {
+	for i := len(links) - 1; i >= 0; i-- {
+		link := links[i]
+
+		switch link.Type {
+		case linkRung:
+			err = &rung{
+				msg:        link.Message,
+				cause:      err,
+				fatal:      link.Fatal,
+				tags:       link.Tags,
+				extras:     link.Extras,
+				ignore:     link.Ignore,
+				code:       link.Code,
+				kind:       link.Kind,
+				retryAfter: time.Duration(link.RetryAfterMs) * time.Millisecond,
+				violations: link.FieldViolations,
+			}
+		case linkAnnotation:
+			err = annotate(err, link.Tags, link.Extras)
+		case linkKind:
+			err = link.Kind
+		case linkMulti:
+			children := make([]error, 0, len(link.Errors))
+			for _, child := range link.Errors {
+				children = append(children, unmarshalChain(child))
+			}
+			err = &joined{msg: link.Message, errs: children}
+		default:
+			err = &wrapped{msg: link.Message, cause: err}
+		}
+
+		if link.Stack != nil {
+			err = &withFrames{cause: err, frames: link.Stack}
+		}
+	}
+	return
+}
+
+// wrapped is an error rebuilt from its message. It is used for the errors of
+// a serialized chain which were not created by this package.
+type wrapped struct {
+	msg   string
+	cause error
+}
+
+func (e *wrapped) Error() string {
+	return e.msg
+}
+
+func (e *wrapped) Cause() error {
+	return e.cause
+}
+
+func (e *wrapped) Unwrap() error {
+	return e.cause
+}
+
+// joined is a rebuilt error which wraps multiple errors
+type joined struct {
+	msg  string
+	errs []error
+}
+
+func (e *joined) Error() string {
+	return e.msg
+}
+
+func (e *joined) Unwrap() []error {
+	return e.errs
+}
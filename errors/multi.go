@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Multi collects multiple errors, for eg. the failures of a batch job.
+// It is safe for concurrent use and its zero value is ready to use:
+//
+//	var errs errors.Multi
+//	for _, item := range items {
+//		if err := process(item); err != nil {
+//			errs.Append(err)
+//		}
+//	}
+//	return errs.ErrorOrNil()
+//
+// A Multi follows the semantics of the errors of this package:
+//   - Fatal is true if any of the errors is fatal.
+//   - Ignore is true if all of the errors are to be ignored.
+//   - Code is the highest code among the errors.
+//   - Tags and Extras are the merge of the ones of the errors, the earlier errors take precedence.
+//
+// errors.Is and errors.As look into every error collected.
+type Multi struct {
+	mutex sync.RWMutex
+	errs  []error
+}
+
+// Append adds errors to the collection. nil errors are skipped.
+func (m *Multi) Append(errs ...error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+}
+
+// Errors returns a copy of the errors collected
+func (m *Multi) Errors() []error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return append([]error(nil), m.errs...)
+}
+
+// Len returns the number of errors collected
+func (m *Multi) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return len(m.errs)
+}
+
+// ErrorOrNil returns nil if no error was collected. Otherwise it returns a
+// Multi with the errors collected so far, which is not affected by any
+// further Append.
+func (m *Multi) ErrorOrNil() error {
+	errs := m.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return &Multi{errs: errs}
+}
+
+// GroupByCode groups the errors collected by their Code()
+func (m *Multi) GroupByCode(defaultCode int) map[int][]error {
+	groups := make(map[int][]error)
+	for _, err := range m.Errors() {
+		code := Code(err, defaultCode)
+		groups[code] = append(groups[code], err)
+	}
+	return groups
+}
+
+func (m *Multi) Error() string {
+	errs := m.Errors()
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%d errors occurred:", len(errs)))
+	for _, err := range errs {
+		builder.WriteString(fmt.Sprintf("\n\t* %v", err))
+	}
+	return builder.String()
+}
+
+// Implementing the unwrap interface of the standard library for errors wrapping multiple errors
+func (m *Multi) Unwrap() []error {
+	return m.Errors()
+}
+
+func (m *Multi) Fatal() bool {
+	for _, err := range m.Errors() {
+		if Fatal(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Multi) Ignore() bool {
+	errs := m.Errors()
+	for _, err := range errs {
+		if !Ignore(err) {
+			return false
+		}
+	}
+	return len(errs) > 0
+}
+
+// Code returns the highest code among the errors collected, 0 if none of them has a code
+func (m *Multi) Code() (code int) {
+	for _, err := range m.Errors() {
+		if c := codeOf(err); c > code {
+			code = c
+		}
+	}
+	return
+}
+
+func (m *Multi) Tags() (tags map[string]string) {
+	for _, err := range m.Errors() {
+		for k, v := range Tags(err) {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			if _, exists := tags[k]; !exists {
+				tags[k] = v
+			}
+		}
+	}
+	return
+}
+
+func (m *Multi) Extras() (extras map[string]interface{}) {
+	for _, err := range m.Errors() {
+		for k, v := range Extras(err) {
+			if extras == nil {
+				extras = make(map[string]interface{})
+			}
+			if _, exists := extras[k]; !exists {
+				extras[k] = v
+			}
+		}
+	}
+	return
+}
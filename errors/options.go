@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"time"
+)
+
+// Option configures an error created with New.
+type Option func(*options)
+
+// options are the settings of an error being created with New
+type options struct {
+	rung
+	stack stackOptions
+}
+
+// New creates an error with a stack, configured using the given options.
+// Any combination of options can be used, for eg.
+//
+//	errors.New("could not reach the ASR vendor",
+//		errors.WithCause(err),
+//		errors.WithFatal(true),
+//		errors.WithCode(http.StatusBadGateway),
+//		errors.WithTags(map[string]string{"vendor": "google"}),
+//	)
+func New(_msg string, opts ...Option) error {
+	return newError(_msg, opts...)
+}
+
+// newError creates the error for New and the other constructors of the package.
+// It must be called directly by the exported constructor, so that the frame
+// of the constructor is the only one skipped from the stack.
+func newError(_msg string, opts ...Option) error {
+	o := options{stack: defaultStackOptions()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	err := o.rung
+	err.msg = _msg
+	return withStackOf(&err, o.stack)
+}
+
+// WithCause chains the error with a cause.
+func WithCause(cause error) Option {
+	return func(e *options) {
+		e.cause = cause
+	}
+}
+
+// WithFatal marks the error as fatal(irrecoverable) or not.
+// It is not called Fatal since Fatal reports whether an error is fatal.
+func WithFatal(fatal bool) Option {
+	return func(e *options) {
+		e.fatal = fatal
+	}
+}
+
+// WithTags adds tags to the error. The map is copied, so changes made to it
+// later on do not affect the error. Using the option multiple times merges the tags.
+func WithTags(tags map[string]string) Option {
+	return func(e *options) {
+		if len(tags) == 0 {
+			return
+		}
+		if e.tags == nil {
+			e.tags = make(map[string]string, len(tags))
+		}
+		for k, v := range tags {
+			e.tags[k] = v
+		}
+	}
+}
+
+// WithExtras adds extras to the error. The map is copied, so changes made to it
+// later on do not affect the error. Using the option multiple times merges the extras.
+func WithExtras(extras map[string]interface{}) Option {
+	return func(e *options) {
+		if len(extras) == 0 {
+			return
+		}
+		if e.extras == nil {
+			e.extras = make(map[string]interface{}, len(extras))
+		}
+		for k, v := range extras {
+			e.extras[k] = v
+		}
+	}
+}
+
+// Ignored informs loggers to ignore the error.
+func Ignored() Option {
+	return func(e *options) {
+		e.ignore = true
+	}
+}
+
+// WithCode attaches an int code(usually an HTTP status code) to the error.
+func WithCode(code int) Option {
+	return func(e *options) {
+		e.code = code
+	}
+}
+
+// WithKind categorises the error.
+func WithKind(kind Kind) Option {
+	return func(e *options) {
+		e.kind = kind
+	}
+}
+
+// WithRetryAfter informs the caller of the duration to wait for before
+// retrying the operation which failed.
+func WithRetryAfter(d time.Duration) Option {
+	return func(e *options) {
+		e.retryAfter = d
+	}
+}
+
+// WithFieldViolation describes a field of a request which failed validation.
+// It can be used multiple times, once for every bad field.
+func WithFieldViolation(field, description string) Option {
+	return func(e *options) {
+		e.violations = append(e.violations, FieldViolation{Field: field, Description: description})
+	}
+}
+
+// WithStackMode overrides the package level stack mode(see SetStackMode) for the error.
+func WithStackMode(mode StackMode) Option {
+	return func(e *options) {
+		e.stack.mode = mode
+	}
+}
+
+// WithoutStack skips capturing the stack of the error.
+// It is meant for errors created on hot paths whose stack is never read.
+func WithoutStack() Option {
+	return WithStackMode(StackNone)
+}
+
+// WithStackDepth overrides the package level stack depth(see SetStackDepth) for the error.
+func WithStackDepth(depth int) Option {
+	return func(e *options) {
+		e.stack.depth = depth
+	}
+}
+
+// WithStackSkip skips the given number of frames from the top of the stack of
+// the error. It is meant for helpers creating errors on behalf of their callers,
+// for eg. WithStackSkip(1) leaves out the frame of the helper.
+func WithStackSkip(skip int) Option {
+	return func(e *options) {
+		e.stack.skip = skip
+	}
+}
@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType is the media type of the body written by ProblemResponder
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of an error response as described by RFC 7807.
+type Problem struct {
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Status        int               `json:"status"`
+	Detail        string            `json:"detail,omitempty"`
+	Instance      string            `json:"instance,omitempty"`
+	TraceID       string            `json:"trace_id,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	InvalidParams []FieldViolation  `json:"invalid_params,omitempty"`
+}
+
+// ProblemResponder writes errors as application/problem+json responses.
+// Only the information which is safe to be shared with the client is written:
+// the status from Code()(see Status), the message of the topmost error of the stack, the
+// field violations and the tags present in PublicTags. Extras, the messages of
+// the causes and the stacktrace are never written.
+type ProblemResponder struct {
+	// TypeBaseURI is prefixed to the kind of the error to build the type of the problem.
+	// If it is empty or the error does not have a kind, the type is "about:blank".
+	TypeBaseURI string
+	// PublicTags is the allow-list of the tags written in the response
+	PublicTags []string
+	// DefaultCode is the status used for errors without a code or a kind. Defaults to 500.
+	DefaultCode int
+}
+
+// DefaultProblemResponder is the ProblemResponder used by WriteProblem
+var DefaultProblemResponder = &ProblemResponder{}
+
+// ProblemHandlerFunc is an http.HandlerFunc which returns an error
+type ProblemHandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ProblemHandle is an httprouter.Handle which returns an error
+type ProblemHandle func(http.ResponseWriter, *http.Request, httprouter.Params) error
+
+// WriteProblem writes the error as an application/problem+json response using DefaultProblemResponder
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultProblemResponder.Write(w, r, err)
+}
+
+// Problem builds the problem describing the error
+func (p *ProblemResponder) Problem(r *http.Request, err error) *Problem {
+	code := p.Status(err)
+	problem := &Problem{
+		Type:          "about:blank",
+		Title:         statusText(code),
+		Status:        code,
+		Detail:        publicMessage(err),
+		InvalidParams: FieldViolations(err),
+	}
+
+	if kind := KindOf(err); kind != Unknown && p.TypeBaseURI != "" {
+		problem.Type = p.TypeBaseURI + string(kind)
+	}
+
+	if r != nil {
+		problem.Instance = r.URL.Path
+		if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.HasTraceID() {
+			problem.TraceID = spanContext.TraceID().String()
+		}
+	}
+
+	tags := Tags(err)
+	for _, key := range p.PublicTags {
+		if value, ok := tags[key]; ok {
+			if problem.Tags == nil {
+				problem.Tags = make(map[string]string)
+			}
+			problem.Tags[key] = value
+		}
+	}
+
+	return problem
+}
+
+// Status returns the HTTP status of the response for the error. The code of the
+// error(see Code) is used only if it is a client or a server error status, since
+// the codes can also be the ones of the application(for eg. 1001). Otherwise the
+// status of its kind is used, falling back to DefaultCode and then to 500.
+func (p *ProblemResponder) Status(err error) int {
+	if code := Code(err, p.DefaultCode); isErrorStatus(code) {
+		return code
+	}
+	// the statuses of the kinds are all error statuses(see statusText for 499)
+	if code := KindOf(err).Code(); code != 0 {
+		return code
+	}
+	if isErrorStatus(p.DefaultCode) {
+		return p.DefaultCode
+	}
+	return http.StatusInternalServerError
+}
+
+// statusText returns the text of an HTTP status, used as the title of the
+// problem. The standard library does not know the text of 499.
+func statusText(code int) string {
+	if code == statusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(code)
+}
+
+// isErrorStatus reports whether the code is a known client or server error status
+func isErrorStatus(code int) bool {
+	return code >= 400 && code < 600 && http.StatusText(code) != ""
+}
+
+// Write writes the error as an application/problem+json response.
+// A Retry-After header is set if the error carries a retry after duration.
+// Nothing is written if the error is nil.
+func (p *ProblemResponder) Write(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	problem := p.Problem(r, err)
+	if d := RetryAfter(err); d > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// HandleFunc converts a handler returning an error into an http.HandlerFunc.
+// The error returned by the handler(if any) is written as a problem.
+func (p *ProblemResponder) HandleFunc(handler ProblemHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(w, r); err != nil {
+			p.Write(w, r, err)
+		}
+	}
+}
+
+// HandleHttpRouter converts a handler returning an error into an httprouter.Handle.
+// The error returned by the handler(if any) is written as a problem.
+func (p *ProblemResponder) HandleHttpRouter(handler ProblemHandle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if err := handler(w, r, params); err != nil {
+			p.Write(w, r, err)
+		}
+	}
+}
+
+// publicMessage returns the message of the topmost rung of the stack.
+// The messages of the causes are left out since they usually describe internals.
+func publicMessage(err error) (msg string) {
+	walk(err, func(err error) bool {
+		if e, ok := err.(*rung); ok && e.msg != "" {
+			msg = e.msg
+			return false
+		}
+		return true
+	})
+	return
+}
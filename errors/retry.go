@@ -0,0 +1,212 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy configures how Retry retries an operation.
+// A zero value for any of the limits means there is no such limit.
+type RetryPolicy struct {
+	// InitialInterval is the time to wait for after the first failed attempt
+	InitialInterval time.Duration
+	// MaxInterval caps the time to wait for between two attempts
+	MaxInterval time.Duration
+	// Multiplier is the factor by which the interval grows after every attempt
+	Multiplier float64
+	// Jitter randomizes the interval by ±Jitter times its value. It should lie between 0 and 1.
+	Jitter float64
+	// MaxAttempts is the maximum number of times the operation is attempted
+	MaxAttempts int
+	// MaxElapsedTime is the maximum time spent retrying the operation
+	MaxElapsedTime time.Duration
+	// Classifier decides if an error can be retried. Defaults to Retryable.
+	// Fatal and ignored errors are never retried irrespective of the classifier.
+	Classifier func(error) bool
+}
+
+// DefaultRetryPolicy is the policy used by the clients in vcore
+var DefaultRetryPolicy = RetryPolicy{
+	InitialInterval: 100 * time.Millisecond,
+	MaxInterval:     5 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.5,
+	MaxAttempts:     3,
+	MaxElapsedTime:  30 * time.Second,
+}
+
+// Retryable reports if the operation which failed with the error can be retried.
+// The logic is as follows:
+//   - nil, fatal and ignored errors are not retryable.
+//   - If an error in the stack implements `Retryable() bool`, the first such error decides.
+//   - Errors of kind Unavailable, Timeout and RateLimited are retryable while the
+//     errors of other kinds(except Internal) are not.
+//   - Errors with a retry after duration are retryable.
+//   - Errors caused by a canceled context or an exceeded deadline are not retryable.
+//   - Any other error is retryable, since it is not fatal.
+func Retryable(err error) bool {
+	if err == nil || Fatal(err) || Ignore(err) {
+		return false
+	}
+
+	type retryable interface {
+		Retryable() bool
+	}
+
+	var decided, isRetryable bool
+	walk(err, func(err error) bool {
+		if check, ok := err.(retryable); ok {
+			decided, isRetryable = true, check.Retryable()
+		}
+		return !decided
+	})
+	if decided {
+		return isRetryable
+	}
+
+	switch KindOf(err) {
+	case Unavailable, Timeout, RateLimited:
+		return true
+	case Unknown, Internal:
+	default:
+		return false
+	}
+
+	if RetryAfter(err) > 0 {
+		return true
+	}
+
+	return !Is(err, context.Canceled) && !Is(err, context.DeadlineExceeded)
+}
+
+// NetworkRetryPolicy is DefaultRetryPolicy retrying only the network errors(see
+// NetworkRetryable). It is used to connect to servers, so that a bad address or
+// rejected credentials fail right away.
+var NetworkRetryPolicy = func() RetryPolicy {
+	policy := DefaultRetryPolicy
+	policy.Classifier = NetworkRetryable
+	return policy
+}()
+
+// NetworkRetryable is a classifier(see RetryPolicy) which retries only the
+// errors which are retryable(see Retryable) and are network errors(net.Error),
+// for eg. a refused connection or a timeout, or are of the kinds Unavailable,
+// Timeout and RateLimited. Unlike Retryable, the other errors are not retried.
+func NetworkRetryable(err error) bool {
+	if !Retryable(err) {
+		return false
+	}
+
+	switch KindOf(err) {
+	case Unavailable, Timeout, RateLimited:
+		return true
+	}
+
+	// a host which does not exist is not going to be found on retrying
+	var dnsErr *net.DNSError
+	if As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+	var netErr net.Error
+	return As(err, &netErr)
+}
+
+// RetryAfter returns the duration to wait for before retrying the operation
+// which failed with the error. The first non-zero duration in the stack is
+// returned, 0 if there is none.
+func RetryAfter(err error) (d time.Duration) {
+	type retryAfterer interface {
+		RetryAfter() time.Duration
+	}
+
+	walk(err, func(err error) bool {
+		if check, ok := err.(retryAfterer); ok {
+			d = check.RetryAfter()
+		}
+		return d == 0
+	})
+
+	return
+}
+
+// Retry calls fn until it succeeds or the policy decides to stop retrying.
+// The time waited for between two attempts grows exponentially as per the
+// policy. If the error carries a retry after duration which is longer, that
+// is waited for instead.
+// Retry stops immediately if the error returned is fatal, to be ignored or not
+// retryable as per the classifier of the policy, or if the context is done.
+// The error of the last attempt is returned with the extras:
+//   - retry_attempts: the number of attempts made
+//   - retry_errors: the messages of the errors of every attempt
+//   - retry_elapsed: the time spent retrying
+func Retry(ctx context.Context, policy RetryPolicy, fn func(context.Context) error) error {
+	classify := policy.Classifier
+	if classify == nil {
+		classify = Retryable
+	}
+
+	start := time.Now()
+	var attempts []string
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		attempts = append(attempts, err.Error())
+
+		result := func(err error) error {
+			return annotate(err, nil, map[string]interface{}{
+				"retry_attempts": attempt,
+				"retry_errors":   attempts,
+				"retry_elapsed":  time.Since(start).String(),
+			})
+		}
+
+		if Fatal(err) || Ignore(err) || !classify(err) {
+			return result(err)
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return result(err)
+		}
+
+		wait := policy.interval(attempt)
+		if d := RetryAfter(err); d > wait {
+			wait = d
+		}
+		if policy.MaxElapsedTime > 0 && time.Since(start)+wait > policy.MaxElapsedTime {
+			return result(err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result(fmt.Errorf("%w (retry aborted: %w)", err, ctx.Err()))
+		case <-timer.C:
+		}
+	}
+}
+
+// interval returns the time to wait for after the given attempt failed
+func (policy RetryPolicy) interval(attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	interval := float64(policy.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxInterval > 0 && interval > float64(policy.MaxInterval) {
+		interval = float64(policy.MaxInterval)
+	}
+
+	if policy.Jitter > 0 {
+		// #nosec G404 -- jitter does not need a cryptographically secure random number
+		interval += interval * policy.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(interval)
+}
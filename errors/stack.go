@@ -0,0 +1,352 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	_err "github.com/pkg/errors"
+)
+
+// StackMode controls how the stack of the errors created by this package is captured
+type StackMode int32
+
+const (
+	// StackFull captures up to the stack depth(see SetStackDepth) frames. It is the default.
+	StackFull StackMode = iota
+	// StackCaller captures only the frame which created the error
+	StackCaller
+	// StackNone does not capture a stack
+	StackNone
+)
+
+// DefaultStackDepth is the maximum number of frames captured by default
+const DefaultStackDepth = 32
+
+// packagePath is the import path of this package. Its frames are never the
+// frames of the application.
+const packagePath = "github.com/skit-ai/vcore/errors"
+
+var (
+	stackMode    atomic.Int32
+	stackDepth   atomic.Int32
+	frameFilters atomic.Pointer[[]FrameFilter]
+)
+
+func init() {
+	stackDepth.Store(DefaultStackDepth)
+}
+
+// SetStackMode sets how the stack of the errors created from now on is captured.
+// It can be overridden for a single error using WithStackMode or WithoutStack.
+func SetStackMode(mode StackMode) {
+	stackMode.Store(int32(mode))
+}
+
+// SetStackDepth sets the maximum number of frames captured for the errors
+// created from now on. A depth of 0 or less disables capturing stacks.
+// It can be overridden for a single error using WithStackDepth.
+func SetStackDepth(depth int) {
+	stackDepth.Store(int32(depth))
+}
+
+// stackOptions are the stack settings of an error being created
+type stackOptions struct {
+	mode  StackMode
+	depth int
+	skip  int
+}
+
+func defaultStackOptions() stackOptions {
+	return stackOptions{
+		mode:  StackMode(stackMode.Load()),
+		depth: int(stackDepth.Load()),
+	}
+}
+
+// Frame is a single frame of the stacktrace of an error
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats the frame compactly, for eg. "main.handler main.go:42".
+// It is meant for log lines where the entire stacktrace is too verbose.
+func (f Frame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, filepath.Base(f.File), f.Line)
+}
+
+// FrameFilter reports whether a frame is to be kept in a stacktrace
+type FrameFilter func(Frame) bool
+
+// SetFrameFilters sets the filters applied to the frames returned by Frames
+// and printed by Stacktrace. A frame is kept only if every filter keeps it.
+// Calling it without filters removes the filters set earlier.
+//
+//	errors.SetFrameFilters(errors.DropRuntime(), errors.DropPrefix("github.com/aws/"))
+func SetFrameFilters(filters ...FrameFilter) {
+	frameFilters.Store(&filters)
+}
+
+// FilterFrames returns the frames kept by all of the filters
+func FilterFrames(frames []Frame, filters ...FrameFilter) []Frame {
+	if len(filters) == 0 {
+		return frames
+	}
+
+	var kept []Frame
+frames:
+	for _, frame := range frames {
+		for _, keep := range filters {
+			if !keep(frame) {
+				continue frames
+			}
+		}
+		kept = append(kept, frame)
+	}
+	return kept
+}
+
+// DropRuntime drops the frames of the go runtime, for eg. runtime.goexit
+func DropRuntime() FrameFilter {
+	return DropPrefix("runtime.")
+}
+
+// DropStdlib drops the frames of the standard library, including the runtime.
+// A package is deemed to be a part of the standard library if the first
+// element of its import path does not contain a dot, for eg. "net/http".
+func DropStdlib() FrameFilter {
+	return func(f Frame) bool {
+		return !isStdlib(f.Function)
+	}
+}
+
+// DropPrefix drops the frames of the functions starting with any of the
+// prefixes. It is meant to drop the frames of modules by their import path,
+// for eg. DropPrefix("github.com/julienschmidt/httprouter").
+func DropPrefix(prefixes ...string) FrameFilter {
+	return func(f Frame) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(f.Function, prefix) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DropVendor drops the frames of vendored packages
+func DropVendor() FrameFilter {
+	return func(f Frame) bool {
+		return !strings.Contains(f.File, "/vendor/")
+	}
+}
+
+func isStdlib(function string) bool {
+	path := function
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	} else if i := strings.IndexByte(path, '.'); i >= 0 {
+		path = path[:i]
+	}
+	return path != "main" && !strings.Contains(path, ".")
+}
+
+// Frames returns the stacktrace of an error as a list of frames.
+// Like Stacktrace, the stacktrace of the deepest error in the stack which has
+// one is returned. nil is returned if no error in the stack has a stacktrace.
+// The filters set using SetFrameFilters are applied.
+func Frames(err error) []Frame {
+	var filters []FrameFilter
+	if set := frameFilters.Load(); set != nil {
+		filters = *set
+	}
+	return FilterFrames(rawFrames(err), filters...)
+}
+
+// Caller returns the first frame of the stacktrace of an error which belongs
+// to the application, i.e. neither to the standard library nor to this package.
+// It is the compact alternative to Stacktrace for log lines:
+//
+//	if frame, ok := errors.Caller(err); ok {
+//		log.Printf("%v (at %s)", err, frame)
+//	}
+func Caller(err error) (Frame, bool) {
+	frames := FilterFrames(Frames(err), DropStdlib(), DropPrefix(packagePath+"."))
+	if len(frames) == 0 {
+		return Frame{}, false
+	}
+	return frames[0], true
+}
+
+// rawFrames returns the unfiltered stacktrace of the deepest error which has one
+func rawFrames(err error) (frames []Frame) {
+	walk(err, func(err error) bool {
+		if own := ownFrames(err); own != nil {
+			frames = own
+		}
+		return true
+	})
+	return
+}
+
+// ownFrames returns the stack captured by the error itself, ignoring its causes
+func ownFrames(err error) []Frame {
+	switch val := err.(type) {
+	case interface{ Frames() []Frame }:
+		return val.Frames()
+	case stackTracer:
+		return framesOf(val.StackTrace())
+	}
+	return nil
+}
+
+// framesOf resolves the frames of a stacktrace captured by pkg/errors
+func framesOf(stack _err.StackTrace) []Frame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, len(stack))
+	for i, f := range stack {
+		pcs[i] = uintptr(f)
+	}
+	return resolve(pcs)
+}
+
+// resolve symbolizes program counters into frames
+func resolve(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := make([]Frame, 0, len(pcs))
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// withStackOf captures the stack of the caller of the exported constructor
+// which created err. Only the program counters are captured; they are
+// resolved into frames the first time the stack is read.
+func withStackOf(err error, opts stackOptions) error {
+	depth := opts.depth
+	switch opts.mode {
+	case StackNone:
+		return err
+	case StackCaller:
+		depth = 1
+	}
+	if depth <= 0 {
+		return err
+	}
+
+	// The program counters are captured on the stack for the usual depths and
+	// only the ones captured are copied to the heap.
+	var buffer [DefaultStackDepth]uintptr
+	pcs := buffer[:]
+	if depth > len(buffer) {
+		pcs = make([]uintptr, depth)
+	}
+
+	// Skipping runtime.Callers, withStackOf, newError and the exported constructor
+	n := runtime.Callers(4+opts.skip, pcs[:depth])
+	return &withStack{cause: err, pcs: append([]uintptr(nil), pcs[:n]...)}
+}
+
+// withStack is a layer carrying the stack captured when an error was created.
+// It implements the StackTrace method of pkg/errors, so that it is understood
+// by the libraries supporting pkg/errors(for eg. sentry).
+type withStack struct {
+	cause error
+	pcs   []uintptr
+
+	once   sync.Once
+	frames []Frame
+}
+
+func (e *withStack) Error() string {
+	return e.cause.Error()
+}
+
+func (e *withStack) Cause() error {
+	return e.cause
+}
+
+func (e *withStack) Unwrap() error {
+	return e.cause
+}
+
+func (e *withStack) StackTrace() _err.StackTrace {
+	stack := make(_err.StackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		stack[i] = _err.Frame(pc)
+	}
+	return stack
+}
+
+func (e *withStack) Frames() []Frame {
+	e.once.Do(func() {
+		e.frames = resolve(e.pcs)
+	})
+	return e.frames
+}
+
+// Format follows the formatting of pkg/errors: %+v prints the message
+// followed by the stacktrace, %v and %s the message and %q the quoted message.
+func (e *withStack) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%+v", e.cause)
+			for _, f := range e.Frames() {
+				_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// withFrames is a layer carrying an already resolved stacktrace.
+// It is used for errors rebuilt from their serialized form, where the program
+// counters of the original stacktrace are not available.
+type withFrames struct {
+	cause  error
+	frames []Frame
+}
+
+func (e *withFrames) Error() string {
+	return e.cause.Error()
+}
+
+func (e *withFrames) Cause() error {
+	return e.cause
+}
+
+func (e *withFrames) Unwrap() error {
+	return e.cause
+}
+
+func (e *withFrames) Frames() []Frame {
+	return e.frames
+}
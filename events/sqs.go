@@ -28,6 +28,7 @@ func getSQSSession() (*awsSession.Session, error) {
 
 
 func getQueueURL(svc *sqs.SQS, queue *string) (*string, error) {
+    // Not retried here, since the SDK retries the failed requests itself.
     result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
         QueueName: queue,
     })
@@ -73,6 +74,7 @@ func SendCostEvent(costEvent CostEvent) {
 			return
 		}
 
+		// The send is not retried, since one which timed out may have gone through. The SDK retries the failed requests itself.
 		_, err = svc.SendMessage(&sqs.SendMessageInput{
 			MessageAttributes: map[string]*sqs.MessageAttributeValue{
 				"EventType": {
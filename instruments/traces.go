@@ -47,15 +47,7 @@ func InitProvider() (func(context.Context) error, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second)
 	defer cancel()
 
-    opts := []grpc.DialOption{
-        grpc.WithBlock(),
-    }
-    if useTls {
-        opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
-    } else {
-        opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-    }
-	conn, err := grpc.DialContext(ctx, collectorEndpoint, opts...)
+	conn, err := DialCollector(ctx, grpc.WithBlock())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create gRPC connection to collector: %w", err)
 	}
@@ -83,11 +75,33 @@ func InitProvider() (func(context.Context) error, error) {
 	return tracerProvider.Shutdown, nil
 }
 
+// DialCollector creates a gRPC connection to the collector at OTEL_COLLECTOR_ENDPOINT,
+// using TLS if OTEL_USE_TLS is set. It is the connection used to export traces
+// and can be shared with the other exporters, for eg. of the logs.
+func DialCollector(ctx context.Context, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if useTls {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	return grpc.DialContext(ctx, collectorEndpoint, opts...)
+}
+
+// ServiceName returns the name of the service set using OTEL_SERVICE_NAME
+func ServiceName() string {
+	return serviceName
+}
+
 // Extracts traceID from a Context
 func ExtractTraceID(ctx context.Context) trace.TraceID {
 	return trace.SpanFromContext(ctx).SpanContext().TraceID()
 }
 
+// Extracts spanID from a Context
+func ExtractSpanID(ctx context.Context) trace.SpanID {
+	return trace.SpanFromContext(ctx).SpanContext().SpanID()
+}
+
 // Set a custom trace span name.
 func SpanNameFormatter(_ string, r *http.Request) string {
 	return fmt.Sprintf("%s %s %s", r.Method, r.Host, r.URL.Path)
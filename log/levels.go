@@ -1,9 +1,16 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/skit-ai/vcore/errors"
 )
@@ -16,11 +23,19 @@ const (
 	TRACE
 )
 
+// Logger logs messages whose level is at most the level of the logger.
+// Loggers are independent of each other and safe for concurrent use; the
+// level can be changed while logging. Use New to create one.
 type Logger struct {
-	level int
+	level      atomic.Int32
+	mutex      sync.Mutex
+	writer     io.Writer
+	format     Format
+	caller     bool
+	timeFormat string
 }
 
-var defaultLogger = Logger{WARN}
+var defaultLogger = New()
 
 // Prefix based on the log level to be added to every log statement
 func levelPrefix(level int) string {
@@ -39,28 +54,115 @@ func levelPrefix(level int) string {
 	return ""
 }
 
-// Logs using stdlib logger based on the log level set
+// LevelName returns the name of a log level, for eg. "warn" for WARN.
+// It is also the name used in the JSON format.
+func LevelName(level int) string {
+	return strings.ToLower(strings.Trim(levelPrefix(level), "[]"))
+}
+
+// ParseLevel returns the log level with the given name, for eg. WARN for "warn"
+func ParseLevel(name string) (int, error) {
+	for level := ERROR; level <= TRACE; level++ {
+		if LevelName(level) == strings.ToLower(strings.TrimSpace(name)) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+// Logs the message if the logger is at the level.
+// The message is written to the writer of the logger or, if it does not have one, using the stdlib logger.
 func (logger *Logger) log(LEVEL int, err error, format string, args ...interface{}) {
-	if logger.isLevel(LEVEL) {
-		if err == nil {
-			log.Printf("%s %s\n", levelPrefix(LEVEL), fmt.Sprintf(format, args...))
-		} else {
-			// Do not use log.Fatalf since it will call os.Exit and terminate the program
-			log.Printf("%s %s:\n%s\n", levelPrefix(LEVEL), fmt.Sprintf(format, args...), errors.Stacktrace(err))
+	if !logger.isLevel(LEVEL) {
+		return
+	}
+
+	// Skipping log and the logging function called
+	var caller string
+	if logger.caller {
+		if _, file, line, ok := runtime.Caller(2); ok {
+			caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+		}
+	}
+
+	line := logger.line(LEVEL, caller, err, fmt.Sprintf(format, args...))
+	if logger.writer == nil {
+		log.Print(line)
+		return
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	_, _ = io.WriteString(logger.writer, line)
+}
+
+// Formats a log line as per the format of the logger
+func (logger *Logger) line(LEVEL int, caller string, err error, msg string) string {
+	// The stdlib logger adds its own timestamp
+	var timestamp string
+	if logger.writer != nil && logger.timeFormat != "" {
+		timestamp = time.Now().Format(logger.timeFormat)
+	}
+
+	if logger.format == FormatJSON {
+		entry := jsonEntry{
+			Time:   timestamp,
+			Level:  LevelName(LEVEL),
+			Caller: caller,
+			Msg:    msg,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			entry.Stack = errors.Frames(err)
 		}
+		encoded, _ := json.Marshal(entry)
+		return string(encoded) + "\n"
+	}
 
+	var builder strings.Builder
+	if timestamp != "" {
+		builder.WriteString(timestamp)
+		builder.WriteString(" ")
+	}
+	builder.WriteString(levelPrefix(LEVEL))
+	if caller != "" {
+		builder.WriteString(" ")
+		builder.WriteString(caller)
+	}
+	builder.WriteString(" ")
+	builder.WriteString(msg)
+	if err != nil {
+		builder.WriteString(":\n")
+		builder.WriteString(errors.Stacktrace(err))
 	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// A log line in the JSON format
+type jsonEntry struct {
+	Time   string         `json:"time,omitempty"`
+	Level  string         `json:"level"`
+	Caller string         `json:"caller,omitempty"`
+	Msg    string         `json:"msg"`
+	Error  string         `json:"error,omitempty"`
+	Stack  []errors.Frame `json:"stack,omitempty"`
 }
 
 // Checks if the logger has the ability to log at a given log level
 func (logger *Logger) isLevel(LEVEL int) bool {
-	return logger.level >= LEVEL
+	return int(logger.level.Load()) >= LEVEL
+}
+
+// Level returns the level of the logger
+func (logger *Logger) Level() int {
+	return int(logger.level.Load())
 }
 
 // Set the level of the logger
 func (logger *Logger) SetLevel(level int) {
 	if level <= TRACE && level >= ERROR {
-		defaultLogger.level = level
+		logger.level.Store(int32(level))
 	} else {
 		_format := "Cannot set log level to %d. Log levels allowed are %s. Default log level is %d(WARN)"
 		logger.Warnf(_format, level, joinInt(",", []int{TRACE, DEBUG, INFO, WARN, ERROR}), WARN)
@@ -167,51 +269,52 @@ func (logger *Logger) Error(err error, args ...interface{}) {
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Methods to log a message using the default logger
+// They call log directly, so that the caller is at the same depth as for the methods of Logger
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // Methods to log a message using the default logger without a format
 
 func Trace(args ...interface{}) {
-	defaultLogger.Trace(args...)
+	defaultLogger.log(TRACE, nil, repeat("%v", len(args)), args...)
 }
 
 func Debug(args ...interface{}) {
-	defaultLogger.Debug(args...)
+	defaultLogger.log(DEBUG, nil, repeat("%v", len(args)), args...)
 }
 
 func Info(args ...interface{}) {
-	defaultLogger.Info(args...)
+	defaultLogger.log(INFO, nil, repeat("%v", len(args)), args...)
 }
 
 func Warn(args ...interface{}) {
-	defaultLogger.Warn(args...)
+	defaultLogger.log(WARN, nil, repeat("%v", len(args)), args...)
 }
 
 func Error(err error, args ...interface{}) {
-	defaultLogger.Error(err, args...)
+	defaultLogger.log(ERROR, err, repeat("%v", len(args)), args...)
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Methods to log messages using the default logger with a format
 
 func Tracef(format string, args ...interface{}) {
-	defaultLogger.Tracef(format, args...)
+	defaultLogger.log(TRACE, nil, format, args...)
 }
 
 func Debugf(format string, args ...interface{}) {
-	defaultLogger.Debugf(format, args...)
+	defaultLogger.log(DEBUG, nil, format, args...)
 }
 
 func Infof(format string, args ...interface{}) {
-	defaultLogger.Infof(format, args...)
+	defaultLogger.log(INFO, nil, format, args...)
 }
 
 func Warnf(format string, args ...interface{}) {
-	defaultLogger.Warnf(format, args...)
+	defaultLogger.log(WARN, nil, format, args...)
 }
 
 func Errorf(err error, format string, args ...interface{}) {
-	defaultLogger.Errorf(err, format, args...)
+	defaultLogger.log(ERROR, err, format, args...)
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -222,6 +325,11 @@ func SetLevel(level int) {
 	defaultLogger.SetLevel(level)
 }
 
+// Level returns the level of the default logger
+func Level() int {
+	return defaultLogger.Level()
+}
+
 // Wrapper for log.Fatal
 func Fatal(v ...interface{}) {
 	log.Fatal(v...)
@@ -0,0 +1,107 @@
+// Package loglevel changes the levels of the log and the log/slog packages of
+// a running service, over HTTP or using signals.
+package loglevel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// Path is the path the Handler is meant to be mounted at
+const Path = "/loglevel"
+
+// Levels are the names of the levels of the default logger of the log package
+// and of the loggers of the log/slog package
+type Levels struct {
+	Log  string `json:"log,omitempty"`
+	Slog string `json:"slog,omitempty"`
+	// SlogNamed overrides the levels of the named loggers of log/slog(see slog.SetLevels)
+	SlogNamed string `json:"slog_named,omitempty"`
+}
+
+// Current returns the current levels
+func Current() Levels {
+	return Levels{
+		Log:       log.LevelName(log.Level()),
+		Slog:      slog.Level(),
+		SlogNamed: slog.LevelsSpec(),
+	}
+}
+
+// Set changes the levels which are not empty. Either all the levels are
+// changed or, if any of them is invalid, none of them is.
+func Set(levels Levels) error {
+	logLevel := log.Level()
+	if levels.Log != "" {
+		level, err := log.ParseLevel(levels.Log)
+		if err != nil {
+			return errors.New(err.Error(), errors.WithKind(errors.InvalidArgument))
+		}
+		logLevel = level
+	}
+
+	slogLevel := strings.ToLower(strings.TrimSpace(levels.Slog))
+	if slogLevel != "" && !slices.Contains(slog.Levels(), slogLevel) {
+		return errors.New(fmt.Sprintf("unknown slog level %q", levels.Slog), errors.WithKind(errors.InvalidArgument))
+	}
+
+	if levels.SlogNamed != "" {
+		if err := slog.SetLevels(levels.SlogNamed); err != nil {
+			return errors.New(err.Error(), errors.WithKind(errors.InvalidArgument))
+		}
+	}
+
+	log.SetLevel(logLevel)
+	if slogLevel != "" {
+		return slog.SetLevel(slogLevel)
+	}
+	return nil
+}
+
+// Handler serves the levels:
+//   - GET responds with the current levels, for eg. {"log": "warn", "slog": "info"}
+//   - PUT changes the levels present in the body and responds with the current levels.
+//     The body is either the levels to be changed, for eg. {"slog": "debug"} or
+//     {"slog_named": "transport.amqp=debug"}, or a level for both the packages,
+//     for eg. {"level": "debug"}.
+//
+// It is usually mounted at Path:
+//
+//	mux.Handle(loglevel.Path, loglevel.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body struct {
+				Level string `json:"level"`
+				Levels
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				errors.WriteProblem(w, r, errors.New("invalid body", errors.WithCause(err), errors.WithKind(errors.InvalidArgument)))
+				return
+			}
+			if body.Level != "" {
+				body.Levels = Levels{Log: body.Level, Slog: body.Level}
+			}
+			if err := Set(body.Levels); err != nil {
+				errors.WriteProblem(w, r, err)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			errors.WriteProblem(w, r, errors.New("method not allowed", errors.WithCode(http.StatusMethodNotAllowed)))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Current())
+	})
+}
@@ -0,0 +1,63 @@
+//go:build unix
+
+package loglevel
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/skit-ai/vcore/log"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// HandleSignals changes the levels on receiving signals until ctx is done:
+//   - SIGUSR1 makes both the packages log more, one level at a time. After the
+//     most verbose level(TRACE for log, debug for slog) it cycles back to the least verbose one.
+//   - SIGUSR2 restores the levels as they were when HandleSignals was called.
+//
+// For eg. `kill -USR1 <pid>` twice on a service logging at WARN makes it log at DEBUG.
+func HandleSignals(ctx context.Context) {
+	initial := Current()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-signals:
+				if sig == syscall.SIGUSR2 {
+					_ = Set(initial)
+					_ = slog.SetLevels(initial.SlogNamed)
+				} else {
+					_ = Set(moreVerbose(Current()))
+				}
+				current := Current()
+				log.Warnf("Received %s, log levels changed to log=%s slog=%s", sig, current.Log, current.Slog)
+			}
+		}
+	}()
+}
+
+// moreVerbose returns the levels one step more verbose than the given ones
+func moreVerbose(levels Levels) Levels {
+	logLevel, _ := log.ParseLevel(levels.Log)
+
+	// slog levels are in the increasing order of severity
+	slogLevels := slog.Levels()
+	slogIndex := slices.Index(slogLevels, levels.Slog) - 1
+	if slogIndex < 0 {
+		slogIndex = len(slogLevels) - 1
+	}
+
+	return Levels{
+		Log:  log.LevelName((logLevel + 1) % (log.TRACE + 1)),
+		Slog: slogLevels[slogIndex],
+	}
+}
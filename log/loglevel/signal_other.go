@@ -0,0 +1,8 @@
+//go:build !unix
+
+package loglevel
+
+import "context"
+
+// HandleSignals does nothing on platforms without SIGUSR1 and SIGUSR2
+func HandleSignals(ctx context.Context) {}
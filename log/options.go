@@ -0,0 +1,82 @@
+package log
+
+import (
+	"io"
+	"time"
+)
+
+// Format is the format of the lines logged by a Logger
+type Format int
+
+const (
+	// FormatText logs lines like "2006-01-02T15:04:05Z [WARN] main.go:12 message"
+	FormatText Format = iota
+	// FormatJSON logs every line as a JSON object with the keys time, level, caller, msg, error and stack
+	FormatJSON
+)
+
+// DefaultTimeFormat is the layout of the timestamp of the loggers with a writer of their own
+const DefaultTimeFormat = time.RFC3339
+
+// Option configures a Logger created with New.
+type Option func(*Logger)
+
+// New creates a Logger independent of the default logger and of any other Logger.
+// By default, it logs at the level WARN in the text format using the stdlib logger:
+//
+//	logger := log.New(
+//		log.WithLevel(log.DEBUG),
+//		log.WithWriter(os.Stderr),
+//		log.WithFormat(log.FormatJSON),
+//		log.WithCaller(),
+//	)
+func New(opts ...Option) *Logger {
+	logger := &Logger{timeFormat: DefaultTimeFormat}
+	logger.level.Store(WARN)
+	for _, opt := range opts {
+		opt(logger)
+	}
+	return logger
+}
+
+// WithLevel sets the level of the logger. Invalid levels are ignored.
+func WithLevel(level int) Option {
+	return func(logger *Logger) {
+		if level <= TRACE && level >= ERROR {
+			logger.level.Store(int32(level))
+		}
+	}
+}
+
+// WithWriter makes the logger write to w instead of using the stdlib logger.
+// Writes are serialized, so w need not be safe for concurrent use.
+func WithWriter(w io.Writer) Option {
+	return func(logger *Logger) {
+		logger.writer = w
+	}
+}
+
+// WithFormat sets the format of the lines logged.
+// The JSON format is meant to be used along with WithWriter, since the stdlib
+// logger prefixes every line with its own timestamp.
+func WithFormat(format Format) Option {
+	return func(logger *Logger) {
+		logger.format = format
+	}
+}
+
+// WithCaller adds the file and the line which logged the message to every line.
+func WithCaller() Option {
+	return func(logger *Logger) {
+		logger.caller = true
+	}
+}
+
+// WithTimeFormat sets the layout(see time.Layout) of the timestamp of every line.
+// An empty layout leaves the timestamp out. It applies only to loggers with a
+// writer, the stdlib logger adds its own timestamp.
+func WithTimeFormat(layout string) Option {
+	return func(logger *Logger) {
+		logger.timeFormat = layout
+	}
+}
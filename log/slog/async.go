@@ -0,0 +1,236 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy decides what an AsyncWriter does with a line when its buffer is full
+type DropPolicy int
+
+const (
+	// Block waits for the buffer to have room for the line
+	Block DropPolicy = iota
+	// DropOldest drops the oldest line in the buffer to make room for the line
+	DropOldest
+	// DropNewest drops the line
+	DropNewest
+)
+
+var dropPolicyNames = []string{Block: "block", DropOldest: "drop-oldest", DropNewest: "drop-newest"}
+
+func (p DropPolicy) String() string {
+	if p < 0 || int(p) >= len(dropPolicyNames) {
+		return fmt.Sprintf("DropPolicy(%d)", int(p))
+	}
+	return dropPolicyNames[p]
+}
+
+// ParseDropPolicy returns the policy with the name "block", "drop-oldest" or "drop-newest"
+func ParseDropPolicy(name string) (DropPolicy, error) {
+	for i, policyName := range dropPolicyNames {
+		if policyName == strings.ToLower(strings.TrimSpace(name)) {
+			return DropPolicy(i), nil
+		}
+	}
+	return Block, fmt.Errorf("unknown drop policy %q, expected one of %s", name, strings.Join(dropPolicyNames, ", "))
+}
+
+// AsyncWriter is an io.Writer queueing the lines in a bounded ring buffer, which
+// are written to the underlying writer by a goroutine of its own. Each call to
+// Write is expected to be a whole line, like the go-kit loggers do.
+type AsyncWriter struct {
+	w      io.Writer
+	policy DropPolicy
+
+	mutex sync.Mutex
+	// cond is signalled whenever a line is queued, lines are written or the writer is closed
+	cond *sync.Cond
+	// lines is the ring buffer, holding count lines starting at head
+	lines       [][]byte
+	head, count int
+	// queued and done are the numbers of lines queued and of the ones written or dropped from the buffer so far
+	queued, done uint64
+	closed       bool
+
+	dropped atomic.Uint64
+	stopped chan struct{}
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to w, buffering up to size lines.
+// Close must be called once done with it to write the lines remaining.
+func NewAsyncWriter(w io.Writer, size int, policy DropPolicy) *AsyncWriter {
+	if size <= 0 {
+		size = 1
+	}
+	a := &AsyncWriter{
+		w:       w,
+		policy:  policy,
+		lines:   make([][]byte, size),
+		stopped: make(chan struct{}),
+	}
+	a.cond = sync.NewCond(&a.mutex)
+	go a.run()
+	return a
+}
+
+// Write queues a copy of the line as per the drop policy. The lines written
+// after the writer is closed are written synchronously.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mutex.Lock()
+	for a.count == len(a.lines) && a.policy == Block && !a.closed {
+		a.cond.Wait()
+	}
+	switch {
+	case a.closed:
+		a.mutex.Unlock()
+		return a.w.Write(p)
+	case a.count == len(a.lines) && a.policy == DropNewest:
+		a.mutex.Unlock()
+		a.dropped.Add(1)
+		return len(p), nil
+	case a.count == len(a.lines):
+		a.lines[a.head] = nil
+		a.head = (a.head + 1) % len(a.lines)
+		a.count--
+		a.done++
+		a.dropped.Add(1)
+	}
+
+	a.lines[(a.head+a.count)%len(a.lines)] = append([]byte(nil), p...)
+	a.count++
+	a.queued++
+	a.mutex.Unlock()
+	a.cond.Broadcast()
+	return len(p), nil
+}
+
+// run writes the lines queued in batches until the writer is closed and the buffer is drained
+func (a *AsyncWriter) run() {
+	defer close(a.stopped)
+
+	var batch []byte
+	for {
+		a.mutex.Lock()
+		for a.count == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if a.count == 0 {
+			a.mutex.Unlock()
+			return
+		}
+
+		batch = batch[:0]
+		n := a.count
+		for i := 0; i < n; i++ {
+			batch = append(batch, a.lines[a.head]...)
+			a.lines[a.head] = nil
+			a.head = (a.head + 1) % len(a.lines)
+		}
+		a.count = 0
+		a.mutex.Unlock()
+		// there is room in the buffer for the writers which are blocked
+		a.cond.Broadcast()
+
+		_, _ = a.w.Write(batch)
+
+		a.mutex.Lock()
+		a.done += uint64(n)
+		a.mutex.Unlock()
+		a.cond.Broadcast()
+	}
+}
+
+// Flush waits for the lines queued so far to be written, or for the context to be done
+func (a *AsyncWriter) Flush(ctx context.Context) error {
+	// wakes up the wait below once the context is done. Taking the mutex makes
+	// sure the wait has either not checked the context yet or is waiting.
+	stop := context.AfterFunc(ctx, func() {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		a.cond.Broadcast()
+	})
+	defer stop()
+
+	a.mutex.Lock()
+	target := a.queued
+	for a.done < target && !a.closed && ctx.Err() == nil {
+		a.cond.Wait()
+	}
+	flushed, closed := a.done >= target, a.closed
+	a.mutex.Unlock()
+
+	switch {
+	case flushed:
+		return nil
+	case closed:
+		// the lines remaining are written before the goroutine of a closed writer stops
+		select {
+		case <-a.stopped:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return ctx.Err()
+	}
+}
+
+// Close writes the lines remaining and stops the writer. The lines written
+// afterwards are written synchronously.
+func (a *AsyncWriter) Close() error {
+	a.mutex.Lock()
+	a.closed = true
+	a.mutex.Unlock()
+	a.cond.Broadcast()
+
+	<-a.stopped
+	return nil
+}
+
+// Dropped returns the number of lines dropped as per the drop policy
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// asyncOutput queues the lines of the loggers when set using SetAsync
+var asyncOutput atomic.Pointer[AsyncWriter]
+
+// SetAsync makes every logger, including the ones created earlier, queue its
+// lines in a buffer of size lines which are written to the output by a goroutine,
+// so that logging does not wait for the lines to be written. The policy decides
+// what happens to the lines logged while the buffer is full. A size of 0 turns
+// it off, writing the lines synchronously. It can also be set using the envs
+// LOG_ASYNC_BUFFER and LOG_DROP_POLICY. Call Close when the service shuts down
+// so that the lines queued are not lost.
+func SetAsync(size int, policy DropPolicy) {
+	var a *AsyncWriter
+	if size > 0 {
+		a = NewAsyncWriter(syncOutput, size, policy)
+	}
+	if previous := asyncOutput.Swap(a); previous != nil {
+		_ = previous.Close()
+	}
+}
+
+// Dropped returns the number of lines dropped as per the drop policy set using SetAsync
+func Dropped() uint64 {
+	if a := asyncOutput.Load(); a != nil {
+		return a.Dropped()
+	}
+	return 0
+}
+
+// lineWriter writes the lines of the loggers to asyncOutput if set, to syncOutput otherwise
+type lineWriter struct{}
+
+func (lineWriter) Write(p []byte) (int, error) {
+	if a := asyncOutput.Load(); a != nil {
+		return a.Write(p)
+	}
+	return syncOutput.Write(p)
+}
@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/skit-ai/vcore/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sentryTraceIDKey is the key of the trace id of the Sentry hub or span carried by a context
+const sentryTraceIDKey = "sentry_trace_id"
+
+// contextKey is the key of the fields stored on a context using ContextWith
+type contextKey struct{}
+
+// contextFields are the fields stored on a context, in the order they were added
+type contextFields struct {
+	keyvals []any
+}
+
+// ContextWith returns a copy of the context carrying the fields along with the
+// ones carried by the context already. The fields are logged by the *Context
+// methods and by the loggers derived using WithTraceId.
+//
+//	ctx = slog.ContextWith(ctx, map[string]any{"call_uuid": callUUID})
+//	slog.InfoContext(ctx, "call started")
+func ContextWith(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	var keyvals []any
+	if existing, ok := ctx.Value(contextKey{}).(*contextFields); ok {
+		keyvals = append(keyvals, existing.keyvals...)
+	}
+	keyvals = append(keyvals, mapToSlice(fields)...)
+	return context.WithValue(ctx, contextKey{}, &contextFields{keyvals: keyvals})
+}
+
+// FieldsFromContext returns the fields logged for a context by the *Context
+// methods and by the loggers derived using WithTraceId, for eg. to log them
+// using another logger.
+func FieldsFromContext(ctx context.Context) map[string]any {
+	keyvals := contextKeyvals(ctx)
+	if len(keyvals) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]any, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fields[keyvals[i].(string)] = keyvals[i+1]
+	}
+	return fields
+}
+
+// contextKeyvals returns the keyvals to log for a context:
+//   - trace_id and span_id of the OpenTelemetry span, only if the span context is valid
+//   - sentry_trace_id of the Sentry span or, if there is none, of the Sentry hub
+//   - the error tags(see errors.ContextWithTags)
+//   - the fields stored using ContextWith
+func contextKeyvals(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+
+	var keyvals []any
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		keyvals = append(keyvals, traceIDKey, spanContext.TraceID(), spanIDKey, spanContext.SpanID())
+	}
+	if traceID := sentryTraceID(ctx); traceID != "" {
+		keyvals = append(keyvals, sentryTraceIDKey, traceID)
+	}
+	for k, v := range errors.TagsFromContext(ctx) {
+		keyvals = append(keyvals, k, v)
+	}
+	if fields, ok := ctx.Value(contextKey{}).(*contextFields); ok {
+		keyvals = append(keyvals, fields.keyvals...)
+	}
+	return keyvals
+}
+
+// sentryTraceID returns the trace id of the Sentry span carried by the context,
+// or of the propagation context of the Sentry hub carried by it if there is no span
+func sentryTraceID(ctx context.Context) string {
+	if span := sentry.SpanFromContext(ctx); span != nil {
+		if span.TraceID == (sentry.TraceID{}) {
+			return ""
+		}
+		return span.TraceID.String()
+	}
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		traceID, _, _ := strings.Cut(hub.GetTraceparent(), "-")
+		if strings.Trim(traceID, "0") == "" {
+			return ""
+		}
+		return traceID
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package slog
+
+import (
+	"sort"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/skit-ai/vcore/errors"
+)
+
+// Keys of the fields of an error, besides the message logged with the key "error"
+const (
+	errorCodeKey   = "error.code"
+	errorKindKey   = "error.kind"
+	errorFatalKey  = "error.fatal"
+	errorStackKey  = "error.stack"
+	errorTagsKey   = "error.tags."
+	errorExtrasKey = "error.extras."
+)
+
+// noCode is passed to errors.Code as the default code, so that the errors
+// without a code can be told apart. It is not a valid HTTP status code.
+const noCode = 1
+
+// errorFields returns the fields of the metadata carried by a vcore error:
+//   - error.code and error.kind, if the error has them
+//   - error.fatal, if any error of the chain can be fatal
+//   - error.tags.<key> and error.extras.<key>, masked as per the redaction
+//     policy by their keys. The extras are left out if the logger is sensitive.
+//   - error.stack, the frames of the stacktrace(see errors.Frames)
+func errorFields(err error, sensitive bool) []any {
+	var fields []any
+	if code := errors.Code(err, noCode); code != noCode {
+		fields = append(fields, errorCodeKey, code)
+	}
+	if kind := errors.KindOf(err); kind != errors.Unknown {
+		fields = append(fields, errorKindKey, string(kind))
+	}
+
+	var fatality interface{ Fatal() bool }
+	if errors.As(err, &fatality) {
+		fields = append(fields, errorFatalKey, errors.Fatal(err))
+	}
+
+	policy := redactionPolicy.Load()
+	tags := errors.Tags(err)
+	for _, k := range sortedKeys(tags) {
+		fields = append(fields, errorTagsKey+k, maskValue(policy, k, tags[k]))
+	}
+	if !sensitive {
+		extras := errors.Extras(err)
+		for _, k := range sortedKeys(extras) {
+			fields = append(fields, errorExtrasKey+k, maskValue(policy, k, extras[k]))
+		}
+	}
+
+	if frames := errors.Frames(err); len(frames) > 0 {
+		stack := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, frame.String())
+		}
+		fields = append(fields, errorStackKey, stack)
+	}
+	return fields
+}
+
+// maskValue masks the value of a field with the key as per the redaction policy
+func maskValue(policy *RedactionPolicy, key string, value any) any {
+	if mask := policy.mask(key); mask != nil {
+		return mask(value)
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// errorLevel returns the level an error is logged at. The errors to be ignored(see errors.Ignore) are logged at debug.
+func errorLevel(err error) func(log.Logger) log.Logger {
+	if err != nil && errors.Ignore(err) {
+		return level.Debug
+	}
+	return level.Error
+}
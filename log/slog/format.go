@@ -0,0 +1,173 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-kit/log"
+	"github.com/skit-ai/vcore/env"
+	"github.com/skit-ai/vcore/instruments"
+)
+
+// Formats of the log lines
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+	// FormatOTLP exports the lines to the OpenTelemetry collector instead of writing them to the output
+	FormatOTLP = "otlp"
+)
+
+// formats create the go-kit loggers writing the lines in each of the formats
+var formats = map[string]func() (log.Logger, error){
+	FormatLogfmt: func() (log.Logger, error) {
+		return log.NewLogfmtLogger(lineWriter{}), nil
+	},
+	FormatJSON: func() (log.Logger, error) {
+		return resourceAdder{next: log.NewJSONLogger(lineWriter{})}, nil
+	},
+	FormatOTLP: startOTLPExporter,
+}
+
+// format is a format along with the go-kit logger writing the lines in it
+type format struct {
+	name   string
+	logger log.Logger
+}
+
+var currentFormat atomic.Pointer[format]
+
+// Format returns the name of the format of the log lines
+func Format() string {
+	if f := currentFormat.Load(); f != nil {
+		return f.name
+	}
+	return FormatLogfmt
+}
+
+// SetFormat changes the format of the lines logged by every logger, including
+// the ones created earlier, to "logfmt", "json" or "otlp". The format can also
+// be set using the env LOG_FORMAT.
+func SetFormat(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	newLogger, ok := formats[name]
+	if !ok {
+		return fmt.Errorf("unknown log format %q, expected one of logfmt, json or otlp", name)
+	}
+
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+	currentFormat.Store(&format{name: name, logger: logger})
+	return nil
+}
+
+// formatter is the go-kit logger at the end of the chain of every logger,
+// writing the lines in the current format
+type formatter struct{}
+
+var defaultFormatter = log.NewLogfmtLogger(lineWriter{})
+
+func (formatter) Log(keyvals ...any) error {
+	if f := currentFormat.Load(); f != nil {
+		return f.logger.Log(keyvals...)
+	}
+	return defaultFormatter.Log(keyvals...)
+}
+
+// openOutput returns the writer for a comma separated list of outputs, each of
+// which is "stdout", "stderr" or the path of a file the lines are appended to.
+func openOutput(spec string) (io.Writer, error) {
+	var writers []io.Writer
+	for _, name := range strings.Split(spec, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open the log output: %w", err)
+			}
+			writers = append(writers, file)
+		}
+	}
+
+	switch len(writers) {
+	case 0:
+		return os.Stderr, nil
+	case 1:
+		return writers[0], nil
+	default:
+		return io.MultiWriter(writers...), nil
+	}
+}
+
+// resource is the keyvals of the resource attributes, sorted by the keys
+var resource atomic.Pointer[[]any]
+
+// defaultResource returns the resource attributes set using the envs
+// OTEL_SERVICE_NAME, RELEASE_VERSION and OTEL_RESOURCE_ATTRIBUTES(a comma
+// separated list of key=value pairs, taking precedence over the other two).
+func defaultResource() map[string]string {
+	attributes := map[string]string{}
+	if name := instruments.ServiceName(); name != "" {
+		attributes["service.name"] = name
+	}
+	if release := env.String("RELEASE_VERSION", ""); release != "" {
+		attributes["release"] = release
+	}
+	for _, pair := range strings.Split(env.String("OTEL_RESOURCE_ATTRIBUTES", ""), ",") {
+		if key, value, ok := strings.Cut(pair, "="); ok && strings.TrimSpace(key) != "" {
+			attributes[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return attributes
+}
+
+// Resource returns the attributes of the resource producing the logs, for eg. service.name
+func Resource() map[string]string {
+	attributes := map[string]string{}
+	if keyvals := resource.Load(); keyvals != nil {
+		for i := 0; i < len(*keyvals); i += 2 {
+			attributes[(*keyvals)[i].(string)] = (*keyvals)[i+1].(string)
+		}
+	}
+	return attributes
+}
+
+// SetResource replaces the attributes of the resource producing the logs. They
+// are exported as the resource in the otlp format and logged as fields of every
+// line in the json format. The lines in the logfmt format are left as they are.
+func SetResource(attributes map[string]string) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyvals := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		keyvals = append(keyvals, key, attributes[key])
+	}
+	resource.Store(&keyvals)
+}
+
+// resourceAdder is a log.Logger adding the resource attributes to the lines
+type resourceAdder struct {
+	next log.Logger
+}
+
+func (r resourceAdder) Log(keyvals ...any) error {
+	attributes := resource.Load()
+	if attributes == nil || len(*attributes) == 0 {
+		return r.next.Log(keyvals...)
+	}
+	return r.next.Log(append(append(make([]any, 0, len(*attributes)+len(keyvals)), *attributes...), keyvals...)...)
+}
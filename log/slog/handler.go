@@ -0,0 +1,151 @@
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"strings"
+)
+
+// handler is a log/slog Handler of the standard library which logs using a Logger
+type handler struct {
+	logger Logger
+	// prefix of the keys of the attributes, made of the groups opened so far
+	prefix string
+}
+
+// Handler returns a Handler of the standard library's log/slog which logs
+// using the default logger. It lets libraries logging with the standard
+// library log through the pipeline of this package:
+//
+//	stdslog.SetDefault(stdslog.New(slog.Handler()))
+func Handler() stdslog.Handler {
+	return NewHandler(defaultLoggerWrapper)
+}
+
+// NewHandler returns a Handler of the standard library's log/slog which logs using the given Logger.
+//   - The levels of the standard library are mapped to the closest level of this package.
+//     Levels below info are logged as debug.
+//   - At the error level, an attribute with the key "err" or "error" whose value is an error is passed to Error.
+//   - Groups are flattened, the keys of the attributes of a group "request" are
+//     prefixed with "request.".
+//   - The fields of the context are logged like the *Context methods of the Logger do.
+func NewHandler(logger Logger) stdslog.Handler {
+	return &handler{logger: logger}
+}
+
+// Enabled reports whether any of the loggers logs at the level. The events of
+// the levels which are disabled are filtered out later on.
+func (h *handler) Enabled(_ context.Context, level stdslog.Level) bool {
+	if _, ok := h.logger.(*loggerWrapper); !ok {
+		return true
+	}
+
+	lowest := currentLevel.Load()
+	if overrides := namedLevels.Load(); overrides != nil {
+		for _, index := range *overrides {
+			lowest = min(lowest, index)
+		}
+	}
+	return levelIndexOf(level) >= lowest
+}
+
+func (h *handler) Handle(ctx context.Context, record stdslog.Record) error {
+	index := levelIndexOf(record.Level)
+
+	var err error
+	args := make([]any, 0, 2*record.NumAttrs())
+	record.Attrs(func(attr stdslog.Attr) bool {
+		if index == errorIndex && err == nil && h.prefix == "" && (attr.Key == "err" || attr.Key == defaultErrKey) {
+			if e, ok := attr.Value.Any().(error); ok {
+				err = e
+				return true
+			}
+		}
+		args = appendAttr(args, h.prefix, attr)
+		return true
+	})
+
+	switch index {
+	case debugIndex:
+		h.logger.DebugContext(ctx, record.Message, args...)
+	case infoIndex:
+		h.logger.InfoContext(ctx, record.Message, args...)
+	case warnIndex:
+		h.logger.WarnContext(ctx, record.Message, args...)
+	default:
+		h.logger.ErrorContext(ctx, err, record.Message, args...)
+	}
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	var fields []any
+	for _, attr := range attrs {
+		fields = appendAttr(fields, h.prefix, attr)
+	}
+
+	// the loggers of this package keep the attributes in their order, like Handle does
+	if l, ok := h.logger.(interface{ withKeyvals(...any) Logger }); ok {
+		return &handler{logger: l.withKeyvals(fields...), prefix: h.prefix}
+	}
+
+	fieldMap := make(map[string]any, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		fieldMap[fields[i].(string)] = fields[i+1]
+	}
+	return &handler{logger: h.logger.WithFields(fieldMap), prefix: h.prefix}
+}
+
+func (h *handler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+// levelIndexOf returns the index in levels of the level closest to a level of the standard library
+func levelIndexOf(level stdslog.Level) int32 {
+	switch {
+	case level < stdslog.LevelInfo:
+		return debugIndex
+	case level < stdslog.LevelWarn:
+		return infoIndex
+	case level < stdslog.LevelError:
+		return warnIndex
+	default:
+		return errorIndex
+	}
+}
+
+// appendAttr appends an attribute to the keyvals of a log line, flattening groups
+func appendAttr(keyvals []any, prefix string, attr stdslog.Attr) []any {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(stdslog.Attr{}) {
+		return keyvals
+	}
+
+	if attr.Value.Kind() == stdslog.KindGroup {
+		// Attributes of a group without a key are inlined
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, child := range attr.Value.Group() {
+			keyvals = appendAttr(keyvals, prefix, child)
+		}
+		return keyvals
+	}
+
+	return append(keyvals, prefix+attr.Key, attr.Value.Any())
+}
+
+// isBridgeFrame reports whether a function belongs to the standard library's
+// log/slog or to the Handler of this package. The caller logged is the first
+// function which does not.
+func isBridgeFrame(function string) bool {
+	return strings.HasPrefix(function, "log/slog.") ||
+		strings.HasPrefix(function, "github.com/skit-ai/vcore/log/slog.(*handler)")
+}
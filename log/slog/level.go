@@ -0,0 +1,189 @@
+package slog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Indexes of the levels in levels
+const (
+	debugIndex int32 = iota
+	infoIndex
+	warnIndex
+	errorIndex
+)
+
+// levels supported by slog, in the increasing order of severity
+var levels = []struct {
+	name   string
+	option level.Option
+}{
+	{"debug", level.AllowDebug()},
+	{"info", level.AllowInfo()},
+	{"warn", level.AllowWarn()},
+	{"error", level.AllowError()},
+}
+
+// nameKey is the type of loggerKey. Being a type of its own, the fields with
+// the key "logger" logged by the callers are not taken for the name of the logger.
+type nameKey struct{}
+
+func (nameKey) String() string {
+	return "logger"
+}
+
+// loggerKey is the key of the name of a named logger in the log lines, logged as "logger"
+var loggerKey = nameKey{}
+
+var (
+	// currentLevel is the index in levels of the level of the loggers
+	currentLevel atomic.Int32
+	// namedLevels are the indexes in levels of the levels of the named loggers, by their names
+	namedLevels atomic.Pointer[map[string]int32]
+)
+
+// Levels returns the names of the levels supported, in the increasing order of severity
+func Levels() []string {
+	names := make([]string, 0, len(levels))
+	for _, l := range levels {
+		names = append(names, l.name)
+	}
+	return names
+}
+
+// Level returns the name of the current level of the loggers
+func Level() string {
+	return levels[currentLevel.Load()].name
+}
+
+// levelIndex returns the index of a level in levels
+func levelIndex(name string) (int32, error) {
+	for i, l := range levels {
+		if l.name == strings.ToLower(strings.TrimSpace(name)) {
+			return int32(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, the levels supported are %s", name, strings.Join(Levels(), ", "))
+}
+
+// SetLevel changes the level of all the loggers, including the ones created
+// earlier. It is safe to be called while logging, for eg. to turn on debug
+// logs on a running service. An error is returned for an unknown level.
+func SetLevel(name string) error {
+	index, err := levelIndex(name)
+	if err != nil {
+		return err
+	}
+	currentLevel.Store(index)
+	return nil
+}
+
+// SetLevels overrides the levels of the named loggers(see Named) using a spec
+// like "transport.amqp=debug,vorm=warn,*=info". The level of a named logger is
+// the one of the most specific name in the spec which is either its name or a
+// prefix of it ending at a dot, for eg. "transport" applies to "transport.amqp"
+// but not to "transporter". "*" or a level without a name sets the level of
+// all the other loggers, like SetLevel.
+// The overrides set earlier are replaced. Nothing is changed if the spec is invalid.
+func SetLevels(spec string) error {
+	overrides := make(map[string]int32)
+	fallback := int32(-1)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, levelName, found := strings.Cut(entry, "=")
+		if !found {
+			name, levelName = "*", entry
+		}
+		name = strings.TrimSpace(name)
+
+		index, err := levelIndex(levelName)
+		if err != nil {
+			return err
+		}
+		if name == "*" {
+			fallback = index
+		} else if name != "" {
+			overrides[name] = index
+		} else {
+			return fmt.Errorf("missing logger name in %q", entry)
+		}
+	}
+
+	namedLevels.Store(&overrides)
+	if fallback >= 0 {
+		currentLevel.Store(fallback)
+	}
+	return nil
+}
+
+// LevelsSpec returns the overrides of the levels of the named loggers in the
+// format of SetLevels, for eg. "transport.amqp=debug,vorm=warn"
+func LevelsSpec() string {
+	overrides := namedLevels.Load()
+	if overrides == nil {
+		return ""
+	}
+
+	entries := make([]string, 0, len(*overrides))
+	for name, index := range *overrides {
+		entries = append(entries, name+"="+levels[index].name)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// levelOf returns the index in levels of the level of the logger with the given name
+func levelOf(name string) int32 {
+	if overrides := namedLevels.Load(); overrides != nil {
+		for {
+			if index, ok := (*overrides)[name]; ok {
+				return index
+			}
+			i := strings.LastIndexByte(name, '.')
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return currentLevel.Load()
+}
+
+// levelFilter filters the log events as per the current level or, for the
+// events of a named logger, as per the level of its name
+type levelFilter struct {
+	filters []log.Logger
+}
+
+func newLevelFilter(next log.Logger) log.Logger {
+	filter := &levelFilter{}
+	for _, l := range levels {
+		filter.filters = append(filter.filters, level.NewFilter(next, l.option))
+	}
+	return filter
+}
+
+func (f *levelFilter) Log(keyvals ...interface{}) error {
+	index := int32(-1)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == loggerKey {
+			if name, ok := keyvals[i+1].(string); ok {
+				index = levelOf(name)
+			}
+			break
+		}
+	}
+	if index < 0 {
+		index = currentLevel.Load()
+	}
+	return f.filters[index].Log(keyvals...)
+}
@@ -0,0 +1,270 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/skit-ai/vcore/instruments"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+)
+
+const (
+	// otlpBatchSize is the maximum number of records exported at once
+	otlpBatchSize = 512
+	// otlpQueueSize is the maximum number of records waiting to be exported, the records logged beyond it are dropped
+	otlpQueueSize = 8192
+	// otlpInterval is the interval at which the records are exported
+	otlpInterval = time.Second
+	// otlpTimeout is the timeout of a periodic export
+	otlpTimeout = 10 * time.Second
+	// scopeName is the name of the instrumentation scope of the records
+	scopeName = "github.com/skit-ai/vcore/log/slog"
+)
+
+// Keys of the trace and the span ids in the log lines
+const (
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// severities are the severities of the records, by the indexes of the levels in levels
+var severities = []logspb.SeverityNumber{
+	debugIndex: logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+	infoIndex:  logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+	warnIndex:  logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+	errorIndex: logspb.SeverityNumber_SEVERITY_NUMBER_ERROR,
+}
+
+// otlpExporter is a log.Logger queueing the lines as records which are exported to the collector in batches
+type otlpExporter struct {
+	mutex   sync.Mutex
+	conn    grpc.ClientConnInterface
+	records []*logspb.LogRecord
+	dropped int
+	start   sync.Once
+	full    chan struct{}
+}
+
+var exporter = &otlpExporter{full: make(chan struct{}, 1)}
+
+// SetOTLPConn sets the connection the lines are exported over in the otlp format,
+// for eg. to share the connection of the trace exporter. By default a connection
+// to the collector at OTEL_COLLECTOR_ENDPOINT is created(see instruments.DialCollector).
+func SetOTLPConn(conn grpc.ClientConnInterface) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.conn = conn
+}
+
+// startOTLPExporter starts exporting the queued records, connecting to the collector unless connected already
+func startOTLPExporter() (log.Logger, error) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	if exporter.conn == nil {
+		conn, err := instruments.DialCollector(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the collector: %w", err)
+		}
+		exporter.conn = conn
+	}
+	exporter.start.Do(func() {
+		go exporter.run()
+	})
+	return exporter, nil
+}
+
+func (e *otlpExporter) Log(keyvals ...any) error {
+	record := newLogRecord(keyvals)
+
+	e.mutex.Lock()
+	if len(e.records) >= otlpQueueSize {
+		e.dropped++
+		e.mutex.Unlock()
+		return fmt.Errorf("dropped the log line, %d lines are waiting to be exported", otlpQueueSize)
+	}
+	e.records = append(e.records, record)
+	full := len(e.records) >= otlpBatchSize
+	e.mutex.Unlock()
+
+	if full {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// run exports the records periodically and whenever a batch is full. As the
+// errors can not be logged using the exporter itself, they are written to os.Stderr.
+func (e *otlpExporter) run() {
+	ticker := time.NewTicker(otlpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.full:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+		if err := e.flush(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "slog: %s\n", err)
+		}
+		cancel()
+	}
+}
+
+// flush exports the queued records in batches. The records which failed to be exported are queued again(see requeue).
+func (e *otlpExporter) flush(ctx context.Context) error {
+	e.mutex.Lock()
+	conn, records, dropped := e.conn, e.records, e.dropped
+	e.records, e.dropped = nil, 0
+	e.mutex.Unlock()
+
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "slog: dropped %d log lines as the export could not keep up\n", dropped)
+	}
+	if conn == nil || len(records) == 0 {
+		return nil
+	}
+
+	client := collogspb.NewLogsServiceClient(conn)
+	resourceLogs := &logspb.ResourceLogs{Resource: &resourcepb.Resource{Attributes: resourceAttributes()}}
+	for len(records) > 0 {
+		batch := records[:min(len(records), otlpBatchSize)]
+		records = records[len(batch):]
+
+		resourceLogs.ScopeLogs = []*logspb.ScopeLogs{{
+			Scope:      &commonpb.InstrumentationScope{Name: scopeName},
+			LogRecords: batch,
+		}}
+		request := &collogspb.ExportLogsServiceRequest{ResourceLogs: []*logspb.ResourceLogs{resourceLogs}}
+		if _, err := client.Export(ctx, request); err != nil {
+			e.requeue(append(batch, records...))
+			return fmt.Errorf("failed to export %d log lines, they are retried on the next export: %w", len(batch)+len(records), err)
+		}
+	}
+	return nil
+}
+
+// requeue queues the records which failed to be exported ahead of the ones
+// logged since. The oldest of them are dropped if the queue does not have room.
+func (e *otlpExporter) requeue(records []*logspb.LogRecord) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	room := max(otlpQueueSize-len(e.records), 0)
+	if len(records) > room {
+		e.dropped += len(records) - room
+		records = records[len(records)-room:]
+	}
+	e.records = append(append(make([]*logspb.LogRecord, 0, len(records)+len(e.records)), records...), e.records...)
+}
+
+// resourceAttributes returns the resource attributes as the attributes of the resource of the records
+func resourceAttributes() []*commonpb.KeyValue {
+	keyvals := resource.Load()
+	if keyvals == nil {
+		return nil
+	}
+
+	attributes := make([]*commonpb.KeyValue, 0, len(*keyvals)/2)
+	for i := 0; i < len(*keyvals); i += 2 {
+		attributes = append(attributes, &commonpb.KeyValue{Key: (*keyvals)[i].(string), Value: anyValue((*keyvals)[i+1])})
+	}
+	return attributes
+}
+
+// newLogRecord converts the keyvals of a line into a record. The level, the msg
+// and the trace and the span ids are set as the fields of the record and the
+// rest of the keyvals, except the timestamp, as its attributes.
+func newLogRecord(keyvals []any) *logspb.LogRecord {
+	now := uint64(time.Now().UnixNano())
+	record := &logspb.LogRecord{TimeUnixNano: now, ObservedTimeUnixNano: now}
+
+	for i := 0; i < len(keyvals); i += 2 {
+		var value any = log.ErrMissingValue
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+
+		switch {
+		case key == "ts":
+			continue
+		case keyvals[i] == level.Key():
+			name := fmt.Sprint(value)
+			if index, err := levelIndex(name); err == nil {
+				record.SeverityNumber = severities[index]
+			}
+			record.SeverityText = strings.ToUpper(name)
+			continue
+		case key == defaultMsgKey && record.Body == nil:
+			record.Body = anyValue(value)
+			continue
+		}
+
+		switch id := value.(type) {
+		case trace.TraceID:
+			if key == traceIDKey {
+				if id.IsValid() {
+					record.TraceId = id[:]
+				}
+				continue
+			}
+		case trace.SpanID:
+			if key == spanIDKey {
+				if id.IsValid() {
+					record.SpanId = id[:]
+				}
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: key, Value: anyValue(value)})
+	}
+	return record
+}
+
+// anyValue converts a value logged into the value of an attribute
+func anyValue(value any) *commonpb.AnyValue {
+	switch v := value.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}}
+	case uint32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v}}
+	case error:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Error()}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+}
@@ -0,0 +1,214 @@
+package slog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	stdslog "log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-kit/log"
+)
+
+// Redacted is logged in place of the values which are redacted entirely
+const Redacted = "[REDACTED]"
+
+// Mask transforms the value of a sensitive field into what is logged instead
+type Mask func(value any) any
+
+// Redact replaces the value entirely
+func Redact() Mask {
+	return func(any) any {
+		return Redacted
+	}
+}
+
+// KeepLast keeps only the last n characters of the value, for eg. "******7890"
+// for a phone number. Values of n characters or less are masked entirely.
+func KeepLast(n int) Mask {
+	return func(value any) any {
+		runes := []rune(fmt.Sprint(value))
+		if len(runes) <= n {
+			return strings.Repeat("*", len(runes))
+		}
+		return strings.Repeat("*", len(runes)-n) + string(runes[len(runes)-n:])
+	}
+}
+
+// Hash replaces the value with a prefix of its SHA-256 hash, so that lines
+// logging the same value can still be correlated, for eg. "sha256:9f86d081884c7d65"
+func Hash() Mask {
+	return func(value any) any {
+		sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// RedactionRule marks the fields whose key is Key(case-insensitive) or matches Pattern as sensitive
+type RedactionRule struct {
+	Key     string
+	Pattern *regexp.Regexp
+	// Mask is applied to the values of the fields. Defaults to Redact.
+	Mask Mask
+}
+
+// RedactionPolicy decides which fields are redacted and how. The first rule
+// matching the key of a field applies.
+type RedactionPolicy struct {
+	Rules []RedactionRule
+}
+
+var redactionPolicy atomic.Pointer[RedactionPolicy]
+
+// SetRedactionPolicy sets the policy applied to the fields(attached using
+// With* or passed as args) of every line logged, including the ones of the
+// loggers created earlier. The policy can also be set using the env LOG_REDACT(see ParseRedactionPolicy).
+func SetRedactionPolicy(policy RedactionPolicy) {
+	redactionPolicy.Store(&policy)
+}
+
+// ParseRedactionPolicy parses a policy from a comma separated list of rules,
+// each of which is a key or a regular expression between slashes optionally
+// followed by "=" and a mask: "redact", "hash" or "last<n>". For eg.
+//
+//	phone=last4,otp,pan=hash,/(?i)transcript/
+//
+// The regular expressions can not contain commas.
+func ParseRedactionPolicy(spec string) (RedactionPolicy, error) {
+	var policy RedactionPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var rule RedactionRule
+		name, maskName := entry, ""
+		if i := strings.LastIndex(entry, "="); i >= 0 && !strings.HasSuffix(entry, "/") {
+			name, maskName = entry[:i], entry[i+1:]
+		}
+
+		if len(name) > 1 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+			pattern, err := regexp.Compile(name[1 : len(name)-1])
+			if err != nil {
+				return RedactionPolicy{}, fmt.Errorf("invalid redaction pattern %s: %w", name, err)
+			}
+			rule.Pattern = pattern
+		} else if name != "" {
+			rule.Key = name
+		} else {
+			return RedactionPolicy{}, fmt.Errorf("missing key in the redaction rule %q", entry)
+		}
+
+		switch {
+		case maskName == "" || maskName == "redact":
+			rule.Mask = Redact()
+		case maskName == "hash":
+			rule.Mask = Hash()
+		case strings.HasPrefix(maskName, "last"):
+			n, err := strconv.Atoi(strings.TrimPrefix(maskName, "last"))
+			if err != nil || n < 0 {
+				return RedactionPolicy{}, fmt.Errorf("invalid mask %q in the redaction rule %q", maskName, entry)
+			}
+			rule.Mask = KeepLast(n)
+		default:
+			return RedactionPolicy{}, fmt.Errorf("unknown mask %q in the redaction rule %q", maskName, entry)
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+// mask returns the mask of the first rule matching the key, nil if none does
+func (policy *RedactionPolicy) mask(key string) Mask {
+	if policy == nil {
+		return nil
+	}
+	for _, rule := range policy.Rules {
+		if (rule.Key != "" && strings.EqualFold(rule.Key, key)) || (rule.Pattern != nil && rule.Pattern.MatchString(key)) {
+			if rule.Mask == nil {
+				return Redact()
+			}
+			return rule.Mask
+		}
+	}
+	return nil
+}
+
+// secret is a value which is always redacted
+type secret struct {
+	value any
+}
+
+// Secret marks a value as sensitive irrespective of its key. It is redacted
+// using the mask of the rule matching its key or, if there is none, entirely.
+// It is also redacted when printed using fmt, for eg. by Infof, or logged using
+// the standard library's log/slog.
+func Secret(value any) any {
+	return secret{value: value}
+}
+
+func (s secret) String() string {
+	return Redacted
+}
+
+func (s secret) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(Redacted))
+}
+
+func (s secret) LogValue() stdslog.Value {
+	return stdslog.StringValue(Redacted)
+}
+
+func (s secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// redactKeyvals returns the keyvals with the values of the sensitive fields masked.
+// The keyvals are copied only if any of them is redacted.
+func redactKeyvals(keyvals []any) []any {
+	policy := redactionPolicy.Load()
+
+	redacted, copied := keyvals, false
+	for i := 1; i < len(keyvals); i += 2 {
+		value := keyvals[i]
+		s, isSecret := value.(secret)
+
+		var mask Mask
+		if policy != nil {
+			key, ok := keyvals[i-1].(string)
+			if !ok {
+				key = fmt.Sprint(keyvals[i-1])
+			}
+			mask = policy.mask(key)
+		}
+		if mask == nil && !isSecret {
+			continue
+		}
+
+		if isSecret {
+			value = s.value
+			if mask == nil {
+				mask = Redact()
+			}
+		}
+		if !copied {
+			redacted, copied = append([]any(nil), keyvals...), true
+		}
+		redacted[i] = mask(value)
+	}
+	return redacted
+}
+
+// redactor is a log.Logger masking the values of the sensitive fields as per the redaction policy
+type redactor struct {
+	next log.Logger
+}
+
+func (r redactor) Log(keyvals ...any) error {
+	return r.next.Log(redactKeyvals(keyvals)...)
+}
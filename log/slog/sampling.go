@@ -0,0 +1,373 @@
+package slog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// DefaultSamplingInterval is the interval of a Sampling which does not set one
+const DefaultSamplingInterval = time.Second
+
+// Sampling limits the number of lines logged, for eg. to keep a failing
+// dependency from flooding the logs with the same error.
+type Sampling struct {
+	// Interval over which the lines are counted, DefaultSamplingInterval by default.
+	// The number of lines suppressed in an interval is logged once it is over.
+	Interval time.Duration
+	// First lines of each message at each level are logged in an interval. The
+	// lines are not sampled by their message if First is 0.
+	First int
+	// Thereafter every Thereafter-th line of the message after the first ones
+	// is logged, none of them if Thereafter is 0. It requires First to be set.
+	Thereafter int
+	// Limits are the maximum number of lines logged per second, by the names of
+	// the levels. The lines are limited using a token bucket per level holding a
+	// second worth of lines, so that short bursts are let through.
+	Limits map[string]float64
+}
+
+// enabled reports whether the sampling drops any lines
+func (s Sampling) enabled() bool {
+	return s.First > 0 || len(s.Limits) > 0
+}
+
+// validate returns an error if the sampling is invalid
+func (s Sampling) validate() error {
+	if s.First < 0 || s.Thereafter < 0 {
+		return fmt.Errorf("invalid sampling %+v, First and Thereafter can not be negative", s)
+	}
+	if s.Thereafter > 0 && s.First == 0 {
+		return fmt.Errorf("invalid sampling %+v, Thereafter requires First to be set", s)
+	}
+	return nil
+}
+
+// sampler keeps the state of the sampling of the lines of the loggers with a name
+type sampler struct {
+	// name of the loggers sampled, "" for the loggers without a sampling of their own
+	name     string
+	sampling Sampling
+	limits   [errorIndex + 1]float64
+
+	mutex       sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+	buckets     [errorIndex + 1]bucket
+	suppressed  int
+	// unreported is the number of lines suppressed in the intervals over, which are yet to be summarized
+	unreported int
+}
+
+// bucket is a token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take takes a token from the bucket filled at the rate, holding up to a second worth of tokens
+func (b *bucket) take(rate float64, now time.Time) bool {
+	capacity := max(rate, 1)
+	if b.last.IsZero() {
+		b.tokens = capacity
+	} else {
+		b.tokens = min(capacity, b.tokens+rate*now.Sub(b.last).Seconds())
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func newSampler(name string, sampling Sampling) (*sampler, error) {
+	if sampling.Interval <= 0 {
+		sampling.Interval = DefaultSamplingInterval
+	}
+	if err := sampling.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &sampler{name: name, sampling: sampling, counts: map[string]int{}}
+	for i := range s.limits {
+		s.limits[i] = -1
+	}
+	for levelName, limit := range sampling.Limits {
+		index, err := levelIndex(levelName)
+		if err != nil {
+			return nil, err
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid limit %v of the level %s", limit, levelName)
+		}
+		s.limits[index] = limit
+	}
+	return s, nil
+}
+
+// allow reports whether a line of the level with the msg is logged
+func (s *sampler) allow(index int32, msg string, now time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.windowStart.IsZero() {
+		s.windowStart = now
+	} else if now.Sub(s.windowStart) >= s.sampling.Interval {
+		s.reset(now)
+	}
+
+	allowed := true
+	if s.sampling.First > 0 {
+		key := levels[index].name + "\x00" + msg
+		s.counts[key]++
+		if n := s.counts[key] - s.sampling.First; n > 0 && (s.sampling.Thereafter == 0 || n%s.sampling.Thereafter != 0) {
+			allowed = false
+		}
+	}
+	if allowed && s.limits[index] >= 0 {
+		allowed = s.buckets[index].take(s.limits[index], now)
+	}
+
+	if !allowed {
+		s.suppressed++
+	}
+	return allowed
+}
+
+// rollover starts a new interval if the current one is over, returning the number of lines suppressed
+// in the intervals over since the last call
+func (s *sampler) rollover(now time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.windowStart.IsZero() && now.Sub(s.windowStart) >= s.sampling.Interval {
+		s.reset(now)
+	}
+	suppressed := s.unreported
+	s.unreported = 0
+	return suppressed
+}
+
+// reset starts a new interval at now. The counts are replaced rather than
+// cleared, so that the memory held by the messages of a burst is released.
+func (s *sampler) reset(now time.Time) {
+	s.unreported += s.suppressed
+	s.windowStart, s.suppressed = now, 0
+	s.counts = map[string]int{}
+}
+
+var (
+	// samplers are the samplers by the names of the loggers, the one of "" applying to every other logger
+	samplers atomic.Pointer[map[string]*sampler]
+	// summaries starts logging the summaries of the lines suppressed
+	summaries sync.Once
+)
+
+// SetSampling sets the sampling of the lines logged by the named loggers with
+// the name or a name starting with it followed by a dot(see Named), the most
+// specific name applying, like SetLevels. The sampling of "" or "*" applies to
+// every other logger. A zero Sampling turns the sampling off.
+// The sampling can also be set using the env LOG_SAMPLING(see ParseSampling).
+func SetSampling(name string, sampling Sampling) error {
+	if name == "*" {
+		name = ""
+	}
+	if err := sampling.validate(); err != nil {
+		return err
+	}
+
+	var s *sampler
+	if sampling.enabled() {
+		var err error
+		if s, err = newSampler(name, sampling); err != nil {
+			return err
+		}
+	}
+
+	for {
+		current := samplers.Load()
+		updated := make(map[string]*sampler)
+		if current != nil {
+			for k, v := range *current {
+				updated[k] = v
+			}
+		}
+		if s != nil {
+			updated[name] = s
+		} else {
+			delete(updated, name)
+		}
+		if samplers.CompareAndSwap(current, &updated) {
+			break
+		}
+	}
+
+	if s != nil {
+		summaries.Do(func() {
+			go logSummaries()
+		})
+	}
+	return nil
+}
+
+// ParseSampling parses the samplings of the loggers from a semicolon separated
+// list of samplings, each of which is a comma separated list of settings
+// optionally preceded by the name of the loggers and ":". The settings are
+// "interval=<duration>", "first=<n>", "thereafter=<n>" and "<level>=<n>" to
+// limit the lines of the level to n per second. For eg.
+//
+//	first=100,thereafter=100,error=50;transport.amqp:first=10,thereafter=0
+func ParseSampling(spec string) (map[string]Sampling, error) {
+	samplings := map[string]Sampling{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, settings := "", entry
+		if i := strings.Index(entry, ":"); i >= 0 && !strings.Contains(entry[:i], "=") {
+			name, settings = strings.TrimSpace(entry[:i]), entry[i+1:]
+		}
+
+		var sampling Sampling
+		for _, setting := range strings.Split(settings, ",") {
+			key, value, found := strings.Cut(strings.TrimSpace(setting), "=")
+			if !found {
+				return nil, fmt.Errorf("invalid sampling setting %q in %q", setting, entry)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+			var err error
+			switch key {
+			case "interval":
+				sampling.Interval, err = time.ParseDuration(value)
+			case "first":
+				sampling.First, err = strconv.Atoi(value)
+			case "thereafter":
+				sampling.Thereafter, err = strconv.Atoi(value)
+			default:
+				if _, err = levelIndex(key); err != nil {
+					return nil, fmt.Errorf("unknown sampling setting %q in %q", key, entry)
+				}
+				var limit float64
+				if limit, err = strconv.ParseFloat(value, 64); err == nil {
+					if sampling.Limits == nil {
+						sampling.Limits = map[string]float64{}
+					}
+					sampling.Limits[key] = limit
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid sampling setting %q in %q: %w", setting, entry, err)
+			}
+		}
+		if err := sampling.validate(); err != nil {
+			return nil, fmt.Errorf("invalid sampling %q: %w", entry, err)
+		}
+		samplings[name] = sampling
+	}
+	return samplings, nil
+}
+
+// setSamplingSpec sets the samplings parsed from the spec(see ParseSampling). Nothing is changed if the spec is invalid.
+func setSamplingSpec(spec string) error {
+	samplings, err := ParseSampling(spec)
+	if err != nil {
+		return err
+	}
+	for name, sampling := range samplings {
+		if _, err := newSampler(name, sampling); err != nil {
+			return err
+		}
+	}
+	for name, sampling := range samplings {
+		_ = SetSampling(name, sampling)
+	}
+	return nil
+}
+
+// samplerOf returns the sampler of the logger with the given name, nil if its lines are not sampled
+func samplerOf(name string) *sampler {
+	current := samplers.Load()
+	if current == nil {
+		return nil
+	}
+	for name != "" {
+		if s, ok := (*current)[name]; ok {
+			return s
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return (*current)[""]
+}
+
+// samplingFilter is a log.Logger dropping the lines as per the sampling of their logger
+type samplingFilter struct {
+	next log.Logger
+}
+
+func (f samplingFilter) Log(keyvals ...any) error {
+	if samplers.Load() == nil {
+		return f.next.Log(keyvals...)
+	}
+
+	var name, msg string
+	index := int32(-1)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch {
+		case keyvals[i] == loggerKey:
+			name, _ = keyvals[i+1].(string)
+		case keyvals[i] == defaultMsgKey && msg == "":
+			msg, _ = keyvals[i+1].(string)
+		case keyvals[i] == level.Key():
+			if value, ok := keyvals[i+1].(level.Value); ok {
+				if found, err := levelIndex(value.String()); err == nil {
+					index = found
+				}
+			}
+		}
+	}
+
+	if s := samplerOf(name); s != nil && index >= 0 && !s.allow(index, msg, time.Now()) {
+		return nil
+	}
+	return f.next.Log(keyvals...)
+}
+
+// logSummaries logs the number of lines suppressed by each sampler once its interval is over
+func logSummaries() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	summary := level.Warn(log.With(newOutput(), "ts", log.DefaultTimestamp))
+	for now := range ticker.C {
+		current := samplers.Load()
+		if current == nil {
+			continue
+		}
+		for _, s := range *current {
+			suppressed := s.rollover(now)
+			if suppressed == 0 {
+				continue
+			}
+			keyvals := []any{defaultMsgKey, fmt.Sprintf("suppressed %d log lines", suppressed), "suppressed", suppressed}
+			if s.name != "" {
+				keyvals = append(keyvals, loggerKey, s.name)
+			}
+			_ = summary.Log(keyvals...)
+		}
+	}
+}
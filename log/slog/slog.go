@@ -3,19 +3,21 @@ package slog
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"sync"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/skit-ai/vcore/env"
-	"github.com/skit-ai/vcore/instruments"
 )
 
 type loggerWrapper struct {
 	logger    log.Logger
-	mutex     sync.Mutex
-	sensitive bool // if sensitive is true, dont log the values
+	sensitive atomic.Bool // if sensitive is true, dont log the values
 }
 
 const defaultMsgKey = "msg"
@@ -32,53 +34,168 @@ func init() {
 	logLevel = env.String("LOG_LEVEL", "info")
 	logSensitive = false
 	callerDepth = env.Int("LOG_CALLER_DEPTH", 4)
-	defaultLoggerWrapper = newloggerWrapper(logLevel, logSensitive)
+	SetResource(defaultResource())
+	outputErr := setOutputSpec(env.String("LOG_OUTPUT", ""))
+	formatErr := SetFormat(env.String("LOG_FORMAT", FormatLogfmt))
+	dropPolicy, asyncErr := ParseDropPolicy(env.String("LOG_DROP_POLICY", Block.String()))
+	SetAsync(env.Int("LOG_ASYNC_BUFFER", 0), dropPolicy)
+	// Invalid or no logLevel means all levels are allowed to be logged
+	if err := SetLevel(logLevel); err != nil {
+		_ = SetLevel("debug")
+	}
+	defaultLoggerWrapper = newloggerWrapper(logSensitive)
+
+	if outputErr != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_OUTPUT", defaultErrKey, outputErr.Error())
+	}
+	if formatErr != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_FORMAT", defaultErrKey, formatErr.Error())
+	}
+	if asyncErr != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_DROP_POLICY", defaultErrKey, asyncErr.Error())
+	}
+
+	if err := SetLevels(env.String("LOG_LEVELS", "")); err != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_LEVELS", defaultErrKey, err.Error())
+	}
+
+	if err := setSamplingSpec(env.String("LOG_SAMPLING", "")); err != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_SAMPLING", defaultErrKey, err.Error())
+	}
+
+	if policy, err := ParseRedactionPolicy(env.String("LOG_REDACT", "")); err != nil {
+		defaultLoggerWrapper.Warn("ignoring LOG_REDACT", defaultErrKey, err.Error())
+	} else {
+		SetRedactionPolicy(policy)
+	}
 }
 
 // NewLogger returns a new instance of Logger.
 func NewLogger() Logger {
-	return newloggerWrapper(logLevel, logSensitive)
+	return newloggerWrapper(logSensitive)
 }
 
-func newloggerWrapper(logLevel string, sensitive bool) *loggerWrapper {
-	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
-	logger = level.NewFilter(logger, levelFilter(logLevel))
+func newloggerWrapper(sensitive bool) *loggerWrapper {
+	logger := newOutput()
+	logger = samplingFilter{next: logger}
+	logger = newLevelFilter(logger)
 	logger = log.With(logger, "ts", log.DefaultTimestamp)
-	logger = log.With(logger, "caller", log.Caller(callerDepth))
+	logger = log.With(logger, "caller", caller(callerDepth))
 
-	return &loggerWrapper{
-		logger:    logger,
-		sensitive: sensitive,
+	wrapper := &loggerWrapper{logger: logger}
+	wrapper.sensitive.Store(sensitive)
+	return wrapper
+}
+
+// newOutput returns the end of the chain of every logger, which redacts the
+// lines, passes them to the sinks(see Tee) and writes them in the current format
+func newOutput() log.Logger {
+	return redactor{next: teeLogger{next: formatter{}}}
+}
+
+// outputWriter writes to the writer set using SetOutput, os.Stderr by default
+type outputWriter struct{}
+
+// writerBox lets writers of different types be stored in an atomic.Value
+type writerBox struct {
+	io.Writer
+}
+
+var (
+	output atomic.Value
+	// syncOutput serializes the writes of all the loggers
+	syncOutput = log.NewSyncWriter(outputWriter{})
+)
+
+func (outputWriter) Write(p []byte) (int, error) {
+	if box, ok := output.Load().(writerBox); ok {
+		return box.Write(p)
 	}
+	return os.Stderr.Write(p)
 }
 
-func levelFilter(logLevel string) level.Option {
-	switch logLevel {
-	case "debug":
-		return level.AllowDebug()
-	case "info":
-		return level.AllowInfo()
-	case "warn":
-		return level.AllowWarn()
-	case "error":
-		return level.AllowError()
-	// Invalid or no logLevel means all levels are allowed to be logged
-	default:
-		return level.AllowAll()
+// SetOutput makes every logger, including the ones created earlier, write to w.
+// A nil writer restores the default, os.Stderr.
+func SetOutput(w io.Writer) {
+	if w == nil {
+		w = os.Stderr
+	}
+	output.Store(writerBox{w})
+}
+
+// Flush waits for the lines queued(see SetAsync) to be written and exports the
+// lines which are yet to be exported in the otlp format, or for the context to be done.
+func Flush(ctx context.Context) error {
+	if a := asyncOutput.Load(); a != nil {
+		if err := a.Flush(ctx); err != nil {
+			return err
+		}
+	}
+	return exporter.flush(ctx)
+}
+
+// Close writes the lines queued(see SetAsync) and exports the lines which are
+// yet to be exported in the otlp format. It is meant to be called when the
+// service shuts down, the lines logged afterwards are written synchronously.
+func Close() error {
+	if a := asyncOutput.Swap(nil); a != nil {
+		_ = a.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+	defer cancel()
+	return exporter.flush(ctx)
+}
+
+// setOutputSpec sets the output to the writer for a list of outputs(see openOutput)
+func setOutputSpec(spec string) error {
+	w, err := openOutput(spec)
+	if err != nil {
+		return err
+	}
+	SetOutput(w)
+	return nil
+}
+
+// derive returns a child of the logger which logs using the given go-kit logger.
+// The child inherits every setting of the logger as it is at the time, later
+// changes made to either of them do not affect the other one.
+func (l *loggerWrapper) derive(logger log.Logger) *loggerWrapper {
+	child := &loggerWrapper{logger: logger}
+	child.sensitive.Store(l.sensitive.Load())
+	return child
+}
+
+// caller returns a Valuer logging the file and the line of the caller at the
+// given depth, like log.Caller. The frames of the standard library's log/slog
+// and of Handler are skipped, so that the caller of the standard library's
+// logger is logged for the lines logged through Handler.
+func caller(depth int) log.Valuer {
+	return func() interface{} {
+		var pcs [16]uintptr
+		n := runtime.Callers(depth+1, pcs[:])
+		frames := runtime.CallersFrames(pcs[:n])
+		for {
+			frame, more := frames.Next()
+			if !more || !isBridgeFrame(frame.Function) {
+				return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+			}
+		}
 	}
 }
 
 func mapToSlice(m map[string]any) []any {
-	var args []any
-	for k, v := range m {
-		args = append(args, k, v)
+	// sorted by the keys, so that the fields are logged in the same order every time
+	args := make([]any, 0, 2*len(m))
+	for _, k := range sortedKeys(m) {
+		args = append(args, k, m[k])
 	}
 	return args
 }
 
 // Info logs a line with level info using the loggerWrapper instance.
 func (l *loggerWrapper) Info(msg string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 	level.Info(log.With(l.logger, defaultMsgKey, msg)).Log(args...)
@@ -86,7 +203,7 @@ func (l *loggerWrapper) Info(msg string, args ...any) {
 
 // Warn logs a line with level warn using the loggerWrapper instance.
 func (l *loggerWrapper) Warn(msg string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -95,7 +212,7 @@ func (l *loggerWrapper) Warn(msg string, args ...any) {
 
 // Debug logs a line with level debug using a loggerWrapper instance.
 func (l *loggerWrapper) Debug(msg string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -104,27 +221,18 @@ func (l *loggerWrapper) Debug(msg string, args ...any) {
 
 // Error logs a line with level error using a loggerWrapper instance.
 // If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
 func (l *loggerWrapper) Error(err error, msg string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
-	if err == nil {
-		level.Error(log.With(l.logger, defaultMsgKey, msg)).Log(args...)
-		return
-	}
-
-	if msg == "" {
-		level.Error(log.With(l.logger, defaultErrKey, err.Error())).Log(args...)
-		return
-	}
-
-	level.Error(log.With(l.logger, defaultMsgKey, msg, defaultErrKey, err.Error())).Log(args...)
+	errorLevel(err)(log.With(l.logger, errorKeyvals(nil, err, msg, l.sensitive.Load())...)).Log(args...)
 }
 
 // Infof logs a format line with level info using the loggerWrapper instance.
 func (l *loggerWrapper) Infof(format string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -133,7 +241,7 @@ func (l *loggerWrapper) Infof(format string, args ...any) {
 
 // Warnf logs a format line with level warn using the loggerWrapper instance.
 func (l *loggerWrapper) Warnf(format string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -142,7 +250,7 @@ func (l *loggerWrapper) Warnf(format string, args ...any) {
 
 // Debugf logs a format line with level debug using the loggerWrapper instance.
 func (l *loggerWrapper) Debugf(format string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -151,32 +259,78 @@ func (l *loggerWrapper) Debugf(format string, args ...any) {
 
 // Errorf logs a format line with level error using a loggerWrapper instance.
 // If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
 func (l *loggerWrapper) Errorf(err error, format string, args ...any) {
-	if l.sensitive {
+	if l.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
-	if err == nil {
-		level.Error(l.logger).Log(defaultMsgKey, fmt.Sprintf(format, args...))
-		return
+	var msg string
+	if err == nil || format != "" {
+		msg = fmt.Sprintf(format, args...)
 	}
+	errorLevel(err)(l.logger).Log(errorKeyvals(nil, err, msg, l.sensitive.Load())...)
+}
 
-	if format == "" {
-		level.Error(l.logger).Log(defaultErrKey, err.Error())
-		return
+// InfoContext logs a line with level info along with the fields of the context(see WithTraceId).
+func (l *loggerWrapper) InfoContext(ctx context.Context, msg string, args ...any) {
+	if l.sensitive.Load() {
+		args = make([]any, 0)
 	}
 
-	level.Error(l.logger).Log(defaultMsgKey, fmt.Sprintf(format, args...), defaultErrKey, err.Error())
+	level.Info(log.With(l.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
 }
 
-// WithTraceId returns a pointer to updated loggerWrapper with trace_id attached to the logger.
-func (l *loggerWrapper) WithTraceId(ctx context.Context) Logger {
-	traceId := instruments.ExtractTraceID(ctx)
-	logger := log.With(l.logger, "trace_id", traceId)
+// WarnContext logs a line with level warn along with the fields of the context(see WithTraceId).
+func (l *loggerWrapper) WarnContext(ctx context.Context, msg string, args ...any) {
+	if l.sensitive.Load() {
+		args = make([]any, 0)
+	}
+
+	level.Warn(log.With(l.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
+}
+
+// DebugContext logs a line with level debug along with the fields of the context(see WithTraceId).
+func (l *loggerWrapper) DebugContext(ctx context.Context, msg string, args ...any) {
+	if l.sensitive.Load() {
+		args = make([]any, 0)
+	}
+
+	level.Debug(log.With(l.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
+}
+
+// ErrorContext logs a line with level error along with the fields of the context(see WithTraceId).
+// If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
+func (l *loggerWrapper) ErrorContext(ctx context.Context, err error, msg string, args ...any) {
+	if l.sensitive.Load() {
+		args = make([]any, 0)
+	}
+
+	errorLevel(err)(log.With(l.logger, errorKeyvals(ctx, err, msg, l.sensitive.Load())...)).Log(args...)
+}
 
-	return &loggerWrapper{
-		logger: logger,
+// errorKeyvals returns the fields of the context followed by the msg, the error and its fields(see errorFields),
+// leaving out the msg if it is empty and the error if it is nil
+func errorKeyvals(ctx context.Context, err error, msg string, sensitive bool) []any {
+	keyvals := contextKeyvals(ctx)
+	if err == nil || msg != "" {
+		keyvals = append(keyvals, defaultMsgKey, msg)
+	}
+	if err != nil {
+		keyvals = append(keyvals, defaultErrKey, err.Error())
+		keyvals = append(keyvals, errorFields(err, sensitive)...)
 	}
+	return keyvals
+}
+
+// WithTraceId returns a pointer to updated loggerWrapper with the fields of the context attached to the logger:
+//   - trace_id and span_id of the span carried by the context, only if the span context is valid
+//   - sentry_trace_id of the Sentry span or hub carried by the context
+//   - the error tags carried by the context(see errors.ContextWithTags)
+//   - the fields stored on the context using ContextWith
+func (l *loggerWrapper) WithTraceId(ctx context.Context) Logger {
+	return l.derive(log.With(l.logger, contextKeyvals(ctx)...))
 }
 
 // WithFields returns a pointer to updated loggerWrapper with custom fields attached to the logger.
@@ -184,30 +338,29 @@ func (l *loggerWrapper) WithFields(fields map[string]any) Logger {
 	fieldArgs := mapToSlice(fields)
 	logger := log.With(l.logger, fieldArgs...)
 
-	return &loggerWrapper{
-		logger: logger,
-	}
+	return l.derive(logger)
+}
+
+// withKeyvals returns a child of the logger with the keyvals attached, in their order
+func (l *loggerWrapper) withKeyvals(keyvals ...any) Logger {
+	return l.derive(log.With(l.logger, keyvals...))
 }
 
 // WithSensitive returns a pointer to updated loggerWrapper with sensitive flag updated to the logger.
 func (l *loggerWrapper) WithSensitive(sensitive bool) Logger {
-	logger := log.With(l.logger)
-
-	return &loggerWrapper{
-		logger:    logger,
-		sensitive: sensitive,
-	}
+	child := l.derive(l.logger)
+	child.sensitive.Store(sensitive)
+	return child
 }
 
+// SetSensitive changes the sensitive flag of the logger. The loggers derived from it earlier are not affected.
 func (l *loggerWrapper) SetSensitive(val bool) {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	l.sensitive = val
+	l.sensitive.Store(val)
 }
 
 // Info logs a line with level Info.
 func Info(msg string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 	level.Info(log.With(defaultLoggerWrapper.logger, defaultMsgKey, msg)).Log(args...)
@@ -215,7 +368,7 @@ func Info(msg string, args ...any) {
 
 // Warn logs a line with level warn.
 func Warn(msg string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 	level.Warn(log.With(defaultLoggerWrapper.logger, defaultMsgKey, msg)).Log(args...)
@@ -223,7 +376,7 @@ func Warn(msg string, args ...any) {
 
 // Debug logs a line with level debug.
 func Debug(msg string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 	level.Debug(log.With(defaultLoggerWrapper.logger, defaultMsgKey, msg)).Log(args...)
@@ -231,27 +384,18 @@ func Debug(msg string, args ...any) {
 
 // Error logs a line with level error.
 // If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
 func Error(err error, msg string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
-	if err == nil {
-		level.Error(log.With(defaultLoggerWrapper.logger, defaultMsgKey, msg)).Log(args...)
-		return
-	}
-
-	if msg == "" {
-		level.Error(log.With(defaultLoggerWrapper.logger, defaultErrKey, err.Error())).Log(args...)
-		return
-	}
-
-	level.Error(log.With(defaultLoggerWrapper.logger, defaultMsgKey, msg, defaultErrKey, err.Error())).Log(args...)
+	errorLevel(err)(log.With(defaultLoggerWrapper.logger, errorKeyvals(nil, err, msg, defaultLoggerWrapper.sensitive.Load())...)).Log(args...)
 }
 
 // Infof logs a format line with level info.
 func Infof(format string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -260,7 +404,7 @@ func Infof(format string, args ...any) {
 
 // Warnf logs a format line with level warn.
 func Warnf(format string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -269,7 +413,7 @@ func Warnf(format string, args ...any) {
 
 // Debugf logs a format line with level debug.
 func Debugf(format string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
 
@@ -278,22 +422,51 @@ func Debugf(format string, args ...any) {
 
 // Errorf logs a format line with level error.
 // If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
 func Errorf(err error, format string, args ...any) {
-	if defaultLoggerWrapper.sensitive {
+	if defaultLoggerWrapper.sensitive.Load() {
+		args = make([]any, 0)
+	}
+
+	var msg string
+	if err == nil || format != "" {
+		msg = fmt.Sprintf(format, args...)
+	}
+	errorLevel(err)(defaultLoggerWrapper.logger).Log(errorKeyvals(nil, err, msg, defaultLoggerWrapper.sensitive.Load())...)
+}
+
+// InfoContext logs a line with level info along with the fields of the context(see WithTraceId).
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	if defaultLoggerWrapper.sensitive.Load() {
 		args = make([]any, 0)
 	}
+	level.Info(log.With(defaultLoggerWrapper.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
+}
 
-	if err == nil {
-		level.Error(defaultLoggerWrapper.logger).Log(defaultMsgKey, fmt.Sprintf(format, args...))
-		return
+// WarnContext logs a line with level warn along with the fields of the context(see WithTraceId).
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	if defaultLoggerWrapper.sensitive.Load() {
+		args = make([]any, 0)
 	}
+	level.Warn(log.With(defaultLoggerWrapper.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
+}
 
-	if format == "" {
-		level.Error(defaultLoggerWrapper.logger).Log(defaultErrKey, err.Error())
-		return
+// DebugContext logs a line with level debug along with the fields of the context(see WithTraceId).
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	if defaultLoggerWrapper.sensitive.Load() {
+		args = make([]any, 0)
 	}
+	level.Debug(log.With(defaultLoggerWrapper.logger, append(contextKeyvals(ctx), defaultMsgKey, msg)...)).Log(args...)
+}
 
-	level.Error(defaultLoggerWrapper.logger).Log(defaultMsgKey, fmt.Sprintf(format, args...), defaultErrKey, err.Error())
+// ErrorContext logs a line with level error along with the fields of the context(see WithTraceId).
+// If err is not nil it adds only the msg string or vice-versa. Otherwise adds both.
+// The code, tags, extras and stack of the error are added as fields and errors.Ignore lowers the level to debug.
+func ErrorContext(ctx context.Context, err error, msg string, args ...any) {
+	if defaultLoggerWrapper.sensitive.Load() {
+		args = make([]any, 0)
+	}
+	errorLevel(err)(log.With(defaultLoggerWrapper.logger, errorKeyvals(ctx, err, msg, defaultLoggerWrapper.sensitive.Load())...)).Log(args...)
 }
 
 // WithFields returns WithFields using the defaultLoggerWrapper.
@@ -301,6 +474,13 @@ func WithFields(fields map[string]any) Logger {
 	return defaultLoggerWrapper.WithFields(fields)
 }
 
+// Named returns a logger whose level can be set by its name using LOG_LEVELS or SetLevels.
+// Names are hierarchical, separated by dots, for eg. "transport.amqp".
+// The name is logged with the key "logger".
+func Named(name string) Logger {
+	return defaultLoggerWrapper.derive(log.With(defaultLoggerWrapper.logger, loggerKey, name))
+}
+
 // WithTraceId returns WithTraceId using the defaultLoggerWrapper.
 func WithTraceId(ctx context.Context) Logger {
 	return defaultLoggerWrapper.WithTraceId(ctx)
@@ -12,6 +12,10 @@ type Logger interface {
 	Warnf(format string, args ...any)
 	Debugf(format string, args ...any)
 	Errorf(err error, format string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	DebugContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, err error, msg string, args ...any)
 	WithTraceId(ctx context.Context) Logger
 	WithFields(fields map[string]any) Logger
 	WithSensitive(bool) Logger
@@ -0,0 +1,217 @@
+// Package slogtest captures the lines logged using slog in tests, so that the
+// tests can assert on them:
+//
+//	func TestCall(t *testing.T) {
+//		t.Parallel()
+//		logger := slogtest.New(t)
+//		handle(logger, call)
+//
+//		slogtest.AssertLogged(t, "info", "call ended", "call_uuid", call.UUID)
+//		slogtest.AssertNoErrors(t)
+//	}
+package slogtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log/level"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// Entry is a line captured
+type Entry struct {
+	Level  string
+	Msg    string
+	Caller string
+	// Fields are the rest of the fields of the line, except the timestamp
+	Fields map[string]any
+}
+
+func (e Entry) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "level=%s msg=%q", e.Level, e.Msg)
+	for _, k := range sortedKeys(e.Fields) {
+		fmt.Fprintf(&builder, " %s=%v", k, e.Fields[k])
+	}
+	return builder.String()
+}
+
+// Logger is a slog.Logger capturing its lines instead of writing them. The
+// loggers derived from it, for eg. using WithFields, capture into it as well.
+type Logger struct {
+	slog.Logger
+	recorder *recorder
+}
+
+// recorder is a go-kit logger converting the keyvals of the lines into entries
+type recorder struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+func (r *recorder) Log(keyvals ...any) error {
+	entry := Entry{Fields: map[string]any{}}
+	for i := 0; i < len(keyvals); i += 2 {
+		var value any
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		switch key := fmt.Sprint(keyvals[i]); {
+		case keyvals[i] == level.Key():
+			entry.Level = fmt.Sprint(value)
+		case key == "msg":
+			entry.Msg = fmt.Sprint(value)
+		case key == "caller":
+			entry.Caller = fmt.Sprint(value)
+		case key == "ts":
+		default:
+			entry.Fields[key] = value
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *recorder) snapshot() []Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+var (
+	// recorders are the recorders of the loggers created for each test which is running
+	recorders      = map[testing.TB][]*recorder{}
+	recordersMutex sync.Mutex
+)
+
+// register makes the assertions on the test check the lines of the recorder until the test ends
+func register(t testing.TB, r *recorder) {
+	recordersMutex.Lock()
+	defer recordersMutex.Unlock()
+	if _, ok := recorders[t]; !ok {
+		t.Cleanup(func() {
+			recordersMutex.Lock()
+			defer recordersMutex.Unlock()
+			delete(recorders, t)
+		})
+	}
+	recorders[t] = append(recorders[t], r)
+}
+
+// New returns a Logger capturing the lines of every level for the test. The
+// lines of the loggers of the tests running in parallel are kept apart, so it
+// is the one to pass to the code under test when it takes a logger.
+func New(t testing.TB) *Logger {
+	r := &recorder{}
+	register(t, r)
+	return &Logger{Logger: slog.NewLoggerTo(r), recorder: r}
+}
+
+// CaptureDefault returns a Logger capturing, along with its own lines, the lines
+// written by every slog logger, including the default one, until the test ends.
+// The lines written are still filtered by the levels(see slog.SetLevel). As
+// every line is captured, the lines logged by the tests running in parallel are
+// captured as well, so assert on the fields which tell the lines of the test apart.
+func CaptureDefault(t testing.TB) *Logger {
+	logger := New(t)
+	t.Cleanup(slog.Tee(logger.recorder))
+	return logger
+}
+
+// Entries returns the lines captured by the logger so far
+func (l *Logger) Entries() []Entry {
+	return l.recorder.snapshot()
+}
+
+// entries returns the lines captured for the test so far(see New and CaptureDefault)
+func entries(t testing.TB) []Entry {
+	recordersMutex.Lock()
+	rs := recorders[t]
+	recordersMutex.Unlock()
+
+	var entries []Entry
+	for _, r := range rs {
+		entries = append(entries, r.snapshot()...)
+	}
+	return entries
+}
+
+// AssertLogged fails the test unless a line with the level and the msg has been
+// captured for the test(see New and CaptureDefault). The fields are key value
+// pairs the line must also have, the values being compared as they are formatted.
+func AssertLogged(t testing.TB, level, msg string, fields ...any) {
+	t.Helper()
+	captured := entries(t)
+	for _, entry := range captured {
+		if entry.Level == level && entry.Msg == msg && hasFields(entry, fields) {
+			return
+		}
+	}
+	t.Errorf("expected a line with level=%s msg=%q%s to be logged, got%s", level, msg, formatFields(fields), formatEntries(captured))
+}
+
+// AssertNoErrors fails the test if any line with level error has been captured for the test(see New and CaptureDefault)
+func AssertNoErrors(t testing.TB) {
+	t.Helper()
+	var errorEntries []Entry
+	for _, entry := range entries(t) {
+		if entry.Level == "error" {
+			errorEntries = append(errorEntries, entry)
+		}
+	}
+	if len(errorEntries) > 0 {
+		t.Errorf("expected no errors to be logged, got%s", formatEntries(errorEntries))
+	}
+}
+
+func hasFields(entry Entry, fields []any) bool {
+	for i := 0; i < len(fields); i += 2 {
+		value, ok := entry.Fields[fmt.Sprint(fields[i])]
+		if !ok {
+			return false
+		}
+		if i+1 < len(fields) && fmt.Sprint(value) != fmt.Sprint(fields[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatFields(fields []any) string {
+	var builder strings.Builder
+	for i := 0; i < len(fields); i += 2 {
+		fmt.Fprintf(&builder, " %v=", fields[i])
+		if i+1 < len(fields) {
+			fmt.Fprintf(&builder, "%v", fields[i+1])
+		}
+	}
+	return builder.String()
+}
+
+func formatEntries(entries []Entry) string {
+	if len(entries) == 0 {
+		return " no lines"
+	}
+	var builder strings.Builder
+	for _, entry := range entries {
+		builder.WriteString("\n\t")
+		builder.WriteString(entry.String())
+	}
+	return builder.String()
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	stdslog "log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+// stdLogger is a Logger which logs using a Logger of the standard library's log/slog
+type stdLogger struct {
+	logger    *stdslog.Logger
+	sensitive atomic.Bool
+}
+
+// FromStdLogger returns a Logger which logs using a Logger of the standard
+// library's log/slog, for eg. to use a handler of the standard library
+// wherever a Logger is expected:
+//
+//	logger := slog.FromStdLogger(stdslog.New(stdslog.NewJSONHandler(os.Stderr, nil)))
+//
+// The error passed to Error and Errorf is logged as the attribute "error" along
+// with its fields(error.code, error.tags.<key> and so on), at the debug level
+// if it is to be ignored(see errors.Ignore). The fields of the context passed
+// to WithTraceId and the *Context methods are logged as attributes and the
+// context is passed on to the handler.
+func FromStdLogger(logger *stdslog.Logger) Logger {
+	return &stdLogger{logger: logger}
+}
+
+// log logs a record with the source set to the caller of the method of stdLogger.
+// The fields of the context are logged before the args.
+func (l *stdLogger) log(ctx context.Context, level stdslog.Level, err error, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if level == stdslog.LevelError && err != nil && errors.Ignore(err) {
+		level = stdslog.LevelDebug
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	if l.sensitive.Load() {
+		args = nil
+	}
+	if keyvals := contextKeyvals(ctx); len(keyvals) > 0 {
+		args = append(keyvals, args...)
+	}
+
+	// Skipping runtime.Callers, log and the method of stdLogger
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := stdslog.NewRecord(time.Now(), level, msg, pcs[0])
+	if err != nil {
+		record.AddAttrs(stdslog.String(defaultErrKey, err.Error()))
+		record.Add(errorFields(err, l.sensitive.Load())...)
+	}
+	record.Add(redactKeyvals(args)...)
+	_ = l.logger.Handler().Handle(ctx, record)
+}
+
+func (l *stdLogger) Info(msg string, args ...any) {
+	l.log(context.Background(), stdslog.LevelInfo, nil, msg, args...)
+}
+
+func (l *stdLogger) Warn(msg string, args ...any) {
+	l.log(context.Background(), stdslog.LevelWarn, nil, msg, args...)
+}
+
+func (l *stdLogger) Debug(msg string, args ...any) {
+	l.log(context.Background(), stdslog.LevelDebug, nil, msg, args...)
+}
+
+func (l *stdLogger) Error(err error, msg string, args ...any) {
+	l.log(context.Background(), stdslog.LevelError, err, msg, args...)
+}
+
+func (l *stdLogger) Infof(format string, args ...any) {
+	l.log(context.Background(), stdslog.LevelInfo, nil, l.sprintf(format, args...))
+}
+
+func (l *stdLogger) Warnf(format string, args ...any) {
+	l.log(context.Background(), stdslog.LevelWarn, nil, l.sprintf(format, args...))
+}
+
+func (l *stdLogger) Debugf(format string, args ...any) {
+	l.log(context.Background(), stdslog.LevelDebug, nil, l.sprintf(format, args...))
+}
+
+func (l *stdLogger) Errorf(err error, format string, args ...any) {
+	l.log(context.Background(), stdslog.LevelError, err, l.sprintf(format, args...))
+}
+
+func (l *stdLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, stdslog.LevelInfo, nil, msg, args...)
+}
+
+func (l *stdLogger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, stdslog.LevelWarn, nil, msg, args...)
+}
+
+func (l *stdLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, stdslog.LevelDebug, nil, msg, args...)
+}
+
+func (l *stdLogger) ErrorContext(ctx context.Context, err error, msg string, args ...any) {
+	l.log(ctx, stdslog.LevelError, err, msg, args...)
+}
+
+// sprintf formats the message, leaving out the args if the logger is sensitive
+func (l *stdLogger) sprintf(format string, args ...any) string {
+	if l.sensitive.Load() {
+		args = nil
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (l *stdLogger) WithTraceId(ctx context.Context) Logger {
+	return l.with(l.logger.With(redactKeyvals(contextKeyvals(ctx))...))
+}
+
+func (l *stdLogger) WithFields(fields map[string]any) Logger {
+	return l.with(l.logger.With(redactKeyvals(mapToSlice(fields))...))
+}
+
+func (l *stdLogger) withKeyvals(keyvals ...any) Logger {
+	return l.with(l.logger.With(redactKeyvals(keyvals)...))
+}
+
+func (l *stdLogger) WithSensitive(sensitive bool) Logger {
+	derived := &stdLogger{logger: l.logger}
+	derived.sensitive.Store(sensitive)
+	return derived
+}
+
+func (l *stdLogger) SetSensitive(val bool) {
+	l.sensitive.Store(val)
+}
+
+// with returns a logger deriving from l which logs using the given Logger of the standard library
+func (l *stdLogger) with(logger *stdslog.Logger) Logger {
+	derived := &stdLogger{logger: logger}
+	derived.sensitive.Store(l.sensitive.Load())
+	return derived
+}
@@ -0,0 +1,77 @@
+package slog
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-kit/log"
+)
+
+// sink is a go-kit logger the lines are passed to(see Tee). It is a pointer so
+// that the sink can be removed even if the same go-kit logger is added twice.
+type sink struct {
+	next log.Logger
+}
+
+var (
+	// sinks are the go-kit loggers the lines of every logger are passed to, replaced as a whole on every change
+	sinks      atomic.Pointer[[]*sink]
+	sinksMutex sync.Mutex
+)
+
+// Tee makes every logger, including the ones created earlier, also pass the
+// keyvals of the lines it writes to next, until the returned function is called.
+// The lines are passed after they are filtered by the levels and sampled, and
+// after the values are redacted, as they are about to be written in the current format.
+func Tee(next log.Logger) (untee func()) {
+	s := &sink{next: next}
+
+	sinksMutex.Lock()
+	defer sinksMutex.Unlock()
+	var current []*sink
+	if p := sinks.Load(); p != nil {
+		current = *p
+	}
+	updated := append(append(make([]*sink, 0, len(current)+1), current...), s)
+	sinks.Store(&updated)
+
+	return func() {
+		sinksMutex.Lock()
+		defer sinksMutex.Unlock()
+		current := *sinks.Load()
+		updated := make([]*sink, 0, len(current))
+		for _, other := range current {
+			if other != s {
+				updated = append(updated, other)
+			}
+		}
+		sinks.Store(&updated)
+	}
+}
+
+// teeLogger is a log.Logger passing the lines to the sinks and then to next
+type teeLogger struct {
+	next log.Logger
+}
+
+func (t teeLogger) Log(keyvals ...any) error {
+	if p := sinks.Load(); p != nil {
+		for _, s := range *p {
+			_ = s.next.Log(keyvals...)
+		}
+	}
+	return t.next.Log(keyvals...)
+}
+
+// NewLoggerTo returns a Logger passing the keyvals of the lines, of every level
+// and with the values redacted, to next instead of writing them. The lines are
+// not sampled either. It is meant to capture the lines logged, for eg. in tests.
+func NewLoggerTo(next log.Logger) Logger {
+	var logger log.Logger = redactor{next: next}
+	logger = log.With(logger, "ts", log.DefaultTimestamp)
+	logger = log.With(logger, "caller", caller(callerDepth))
+
+	wrapper := &loggerWrapper{logger: logger}
+	wrapper.sensitive.Store(logSensitive)
+	return wrapper
+}
@@ -2,8 +2,12 @@ package surveillance
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	sentryhttp "github.com/getsentry/sentry-go/http"
@@ -18,55 +22,117 @@ import (
 )
 
 type Sentry struct {
-	client  *sentry.Client
-	handler *sentryWrapper.Handler
+	client    *sentry.Client
+	handler   *sentryWrapper.Handler
+	closeOnce sync.Once
 }
 
-func InitSentry(release string) (client *Sentry) {
-	dsn := env.String("SENTRY_DSN", "")             // Retrieve the Sentry DSN from environment variables
-	sampleRate := env.Float("SENTRY_SAMPLING", 1.0) // Retrieve the Sentry sampling rate from environment variables, defaulting to 1.0
+// defaultCloseTimeout is the time Close waits for the events to be sent
+const defaultCloseTimeout = 2 * time.Second
+
+// Options configure the Sentry client created with New
+type Options struct {
+	Dsn         string
+	Environment string
+	Release     string
+	// SampleRate is the rate at which the errors are sent, 1.0 if 0
+	SampleRate       float64
+	EnableTracing    bool
+	TracesSampleRate float64
+	// BeforeSend is called with every event before it is sent. Returning nil drops the event.
+	BeforeSend func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+	// ServerName is the name of the server the events are sent from, the hostname by default
+	ServerName string
+	// Transport sends the events, asynchronously over HTTP by default. Use sentry.NewHTTPSyncTransport() for testing.
+	Transport sentry.Transport
+}
+
+// OptionsFromEnv returns the options set using the envs SENTRY_DSN, SENTRY_SAMPLING,
+// SENTRY_RELEASE(unless a release is given), SENTRY_TRACING, SENTRY_TRACES_SAMPLE_RATE and ENVIRONMENT
+func OptionsFromEnv(release string) Options {
 	if release == "" {
-		release = env.String("SENTRY_RELEASE", "") // Retrieve the Sentry release version from environment variables if not provided
+		release = env.String("SENTRY_RELEASE", "")
 	}
-	// Parse SENTRY_TRACING environment variable using vcore/env to determine if tracing is enabled
-	enableTracing := env.Bool("SENTRY_TRACING", false)
-	tracesSampleRate := env.Float("SENTRY_TRACES_SAMPLE_RATE", 0.0)
-
-	if dsn != "" {
-		if err := sentry.Init(sentry.ClientOptions{
-			Dsn:              dsn,
-			AttachStacktrace: true,
-			EnableTracing:    enableTracing,
-			TracesSampleRate: tracesSampleRate,
-			// Use async transport. Which is set by default. Use Sync transport for testing.
-			//Transport: sentry.NewHTTPSyncTransport(),
-
-			// Enable debugging to check connectivity
-			//Debug: true,
-			Release:    release,
-			SampleRate: sampleRate,
-
-			Environment: os.Getenv("ENVIRONMENT"),
-		}); err != nil {
-			log.Warnf("Could not initialize sentry with DSN: %s", dsn)
-			client = &Sentry{nil, nil}
-		} else {
-			client = &Sentry{
-				sentry.CurrentHub().Client(),
-				sentryWrapper.New(sentryhttp.Options{Repanic: true}),
-			}
-		}
-	} else {
-		log.Warnf("Could not initialize sentry with DSN: %s", dsn)
-		client = &Sentry{nil, nil}
+	return Options{
+		Dsn:              env.String("SENTRY_DSN", ""),
+		Environment:      os.Getenv("ENVIRONMENT"),
+		Release:          release,
+		SampleRate:       env.Float("SENTRY_SAMPLING", 1.0),
+		EnableTracing:    env.Bool("SENTRY_TRACING", false),
+		TracesSampleRate: env.Float("SENTRY_TRACES_SAMPLE_RATE", 0.0),
+	}
+}
+
+// New initializes the Sentry client of the current hub with the options. With
+// an empty Dsn, the client is not initialized and the errors captured are only
+// logged. Call Close when the service shuts down, so that the events which are
+// yet to be sent are not lost(see CloseOnSignal).
+func New(opts Options) (*Sentry, error) {
+	if opts.Dsn == "" {
+		return &Sentry{}, nil
+	}
+
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:              opts.Dsn,
+		AttachStacktrace: true,
+		EnableTracing:    opts.EnableTracing,
+		TracesSampleRate: opts.TracesSampleRate,
+		Release:          opts.Release,
+		SampleRate:       opts.SampleRate,
+		Environment:      opts.Environment,
+		BeforeSend:       opts.BeforeSend,
+		ServerName:       opts.ServerName,
+		Transport:        opts.Transport,
+	}); err != nil {
+		return nil, err
+	}
+	return &Sentry{
+		client:  sentry.CurrentHub().Client(),
+		handler: sentryWrapper.New(sentryhttp.Options{Repanic: true}),
+	}, nil
+}
+
+// InitSentry initializes the Sentry client using the envs(see OptionsFromEnv).
+// If it can not be initialized, the errors captured are only logged.
+func InitSentry(release string) (client *Sentry) {
+	opts := OptionsFromEnv(release)
+	client, err := New(opts)
+	if err != nil || client.client == nil {
+		log.Warnf("Could not initialize sentry with DSN: %s", opts.Dsn)
+		client = &Sentry{}
 	}
 	return
 }
 
 var (
+	// SentryClient is the client initialized using the envs(see InitSentry)
 	SentryClient = InitSentry("")
 )
 
+// Flush waits for the events captured so far to be sent, for at most the
+// timeout. It returns false if the timeout was reached before they were sent.
+func (wrapper *Sentry) Flush(timeout time.Duration) bool {
+	if wrapper.client == nil {
+		return true
+	}
+	return wrapper.client.Flush(timeout)
+}
+
+// Close waits for the events captured so far to be sent, for at most 2
+// seconds, and stops the transport. The events captured afterwards are not
+// sent. It is meant to be called when the service shuts down.
+func (wrapper *Sentry) Close() {
+	if wrapper.client == nil {
+		return
+	}
+	wrapper.closeOnce.Do(func() {
+		if !wrapper.client.Flush(defaultCloseTimeout) {
+			log.Warnf("Timed out sending the sentry events, some of them may be lost")
+		}
+		wrapper.client.Close()
+	})
+}
+
 // Handles an error by capturing it on Sentry and logging the same on STDOUT
 func (wrapper *Sentry) Capture(err error, _panic bool) sentry.EventID {
 	eventID := new(sentry.EventID)
@@ -91,7 +157,7 @@ func (wrapper *Sentry) Capture(err error, _panic bool) sentry.EventID {
 
 				// Capturing the error on Sentry
 				// eventID can be nil when sample rate is used
-				eventID = sentry.CaptureException(err)
+				eventID = captureException(sentry.CurrentHub(), err)
 				if eventID != nil {
 					log.Errorf(err, "Error captured in sentry with the event ID `%s`", *eventID)
 				}
@@ -115,16 +181,19 @@ func (wrapper *Sentry) Capture(err error, _panic bool) sentry.EventID {
 	return ""
 }
 
-// Handles an error by capturing it on Sentry and logging the same on STDOUT
+// Handles an error by capturing it on Sentry and logging the same on STDOUT.
+// The tags and extras carried by the context(see errors.ContextWithTags) are added to the error.
 func (wrapper *Sentry) CaptureWithContext(c context.Context, err error, _panic bool) sentry.EventID {
 	eventID := new(sentry.EventID)
 	if err != nil {
+		err = errors.FromContext(c, err)
+
 		// Do not log to sentry if the error is ignorable.
 		// However, do log it to stdout
 		if wrapper.client != nil && !errors.Ignore(err) {
 			// Capture error asynchronously
 			if hub := sentry.GetHubFromContext(c); hub != nil {
-				sentry.WithScope(func(scope *sentry.Scope) {
+				hub.WithScope(func(scope *sentry.Scope) {
 					// Setting the stacktrace of the error as an extra along with any other extras set in the error
 					if extras := errors.Extras(err); extras != nil {
 						scope.SetContext("extras", extras)
@@ -136,16 +205,17 @@ func (wrapper *Sentry) CaptureWithContext(c context.Context, err error, _panic b
 
 					// Determining the tags(if any) set on the error
 					scope.SetTags(errors.Tags(err))
+
+					// Capturing the error on Sentry
+					eventID = captureException(hub, err)
 				})
 
-				// Capturing the error on Sentry
-				eventID = hub.CaptureException(err)
 				if eventID != nil {
 					log.Errorf(err, "Error captured in sentry with the event ID `%s`", *eventID)
 				}
 				// NOTE: logging nil events was causing logs to be cluttered with warning logs, hence skipping.
 			} else {
-				wrapper.Capture(err, _panic)
+				return wrapper.Capture(err, _panic)
 			}
 		} else {
 			// Log the error sans sentry's event ID information
@@ -228,7 +298,7 @@ func (wrapper *Sentry) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		resp, err = handler(ctx, req)
 
 		if opts.ReportOn(err) {
-			hub.CaptureException(err)
+			captureException(hub, err)
 		}
 
 		return resp, err
@@ -270,9 +340,53 @@ func (wrapper *Sentry) StreamServerInterceptor() grpc.StreamServerInterceptor {
 		err := handler(srv, wrapped)
 
 		if opts.ReportOn(err) {
-			hub.CaptureException(err)
+			captureException(hub, err)
 		}
 
 		return err
 	}
 }
+
+// captureException captures an error on the hub.
+// An errors.Multi is captured as a single event in which each of the errors
+// collected is a separate exception. As sentry expects the most recent exception
+// to be last, the errors collected come first, followed by the group.
+func captureException(hub *sentry.Hub, err error) *sentry.EventID {
+	var multi *errors.Multi
+	if !errors.As(err, &multi) || multi.Len() == 0 {
+		return hub.CaptureException(err)
+	}
+
+	children := multi.Errors()
+	// the id of an exception is its index in the event
+	groupID := len(children)
+
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelError
+	for i, child := range children {
+		event.Exception = append(event.Exception, sentry.Exception{
+			Type:       reflect.TypeOf(child).String(),
+			Value:      child.Error(),
+			Stacktrace: sentry.ExtractStacktrace(child),
+			Mechanism: &sentry.Mechanism{
+				Type:        "chained",
+				Source:      fmt.Sprintf("errors[%d]", i),
+				ExceptionID: i,
+				ParentID:    sentry.Pointer(groupID),
+			},
+		})
+	}
+
+	event.Exception = append(event.Exception, sentry.Exception{
+		Type:       reflect.TypeOf(err).String(),
+		Value:      err.Error(),
+		Stacktrace: sentry.ExtractStacktrace(err),
+		Mechanism: &sentry.Mechanism{
+			Type:             "generic",
+			IsExceptionGroup: true,
+			ExceptionID:      groupID,
+		},
+	})
+
+	return hub.CaptureEvent(event)
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package surveillance
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/skit-ai/vcore/log"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// CloseOnSignal closes the client(see Close) on receiving SIGTERM, until ctx
+// is done, and then writes the log lines queued by slog(see slog.Close). Once
+// closed, SIGTERM is raised again so that the service terminates as it would
+// have without CloseOnSignal. The services handling SIGTERM themselves should
+// call Close and slog.Close as part of their shutdown instead.
+func (wrapper *Sentry) CloseOnSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-ctx.Done():
+		case <-signals:
+			wrapper.Close()
+			closeLogs()
+			signal.Stop(signals)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		}
+	}()
+}
+
+// closeLogs writes the lines queued by slog, waiting for at most defaultCloseTimeout.
+// The writer is closed only once flushed, as closing waits for the writes to be done.
+func closeLogs() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCloseTimeout)
+	defer cancel()
+
+	if err := slog.Flush(ctx); err != nil {
+		log.Warnf("Timed out writing the log lines, some of them may be lost")
+		return
+	}
+	_ = slog.Close()
+}
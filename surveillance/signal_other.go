@@ -0,0 +1,8 @@
+//go:build !unix
+
+package surveillance
+
+import "context"
+
+// CloseOnSignal does nothing on platforms without SIGTERM
+func (wrapper *Sentry) CloseOnSignal(ctx context.Context) {}
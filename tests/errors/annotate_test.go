@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestAttachTags(t *testing.T) {
+	err := errors.NewErrorWithTags("failed", nil, true, map[string]string{"vendor": "google", "call_uuid": "1"})
+	err = errors.AttachExtras(errors.New("handler failed", errors.WithCause(err), errors.WithCode(http.StatusBadGateway)), map[string]interface{}{"attempt": 1})
+
+	tagged := errors.AttachTags(err, map[string]string{"call_uuid": "2", "flow": "greeting"})
+
+	tags := errors.Tags(tagged)
+	if tags["vendor"] != "google" || tags["call_uuid"] != "2" || tags["flow"] != "greeting" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if errors.Extras(tagged)["attempt"] != 1 {
+		t.Errorf("expected the extras to be kept, got %v", errors.Extras(tagged))
+	}
+	if errors.Fatal(tagged) != errors.Fatal(err) || errors.Code(tagged, 0) != http.StatusBadGateway {
+		t.Error("expected the fatality and the code to be kept")
+	}
+	if errors.Stacktrace(tagged) != errors.Stacktrace(err) {
+		t.Error("expected the stack to be kept")
+	}
+	if tagged.Error() != err.Error() {
+		t.Errorf("unexpected message %q", tagged.Error())
+	}
+
+	// The original error is untouched
+	if got := errors.Tags(err)["call_uuid"]; got != "1" {
+		t.Errorf("expected the original error to be untouched, got call_uuid %s", got)
+	}
+}
+
+func TestAttachTagsToAnyError(t *testing.T) {
+	cause := fmt.Errorf("wrapped: %w", errors.NotFound)
+
+	tagged := errors.AttachTags(cause, map[string]string{"table": "calls"})
+	if errors.Tags(tagged)["table"] != "calls" {
+		t.Errorf("unexpected tags %v", errors.Tags(tagged))
+	}
+	if !errors.Is(tagged, errors.NotFound) {
+		t.Error("expected the chain to be kept")
+	}
+
+	if errors.AttachTags(nil, map[string]string{"table": "calls"}) != nil {
+		t.Error("expected nil for a nil error")
+	}
+
+	// Deprecated helpers behave the same
+	if errors.Tags(errors.AddTagsToError(cause, map[string]string{"table": "calls"}))["table"] != "calls" {
+		t.Error("expected AddTagsToError to tag any error")
+	}
+}
+
+func TestAttachTagsConcurrently(t *testing.T) {
+	err := errors.NewErrorWithTags("failed", nil, false, map[string]string{"vendor": "google"})
+	err = errors.AttachTags(err, map[string]string{"flow": "greeting"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := strconv.Itoa(i)
+			tagged := errors.AttachExtras(errors.AttachTags(err, map[string]string{"id": id}), map[string]interface{}{"id": i})
+			if errors.Tags(tagged)["id"] != id || errors.Extras(tagged)["id"] != i {
+				t.Errorf("unexpected metadata %v %v", errors.Tags(tagged), errors.Extras(tagged))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := errors.Tags(err)["id"]; ok {
+		t.Error("expected the shared error to be untouched")
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestContextWithTags(t *testing.T) {
+	ctx := errors.ContextWithTags(context.Background(), map[string]string{"call_uuid": "1", "flow_uuid": "2"})
+	ctx = errors.ContextWithTags(ctx, map[string]string{"flow_uuid": "3"})
+	ctx = errors.ContextWithExtras(ctx, map[string]interface{}{"turn": 4})
+
+	tags := errors.TagsFromContext(ctx)
+	if tags["call_uuid"] != "1" || tags["flow_uuid"] != "3" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if errors.ExtrasFromContext(ctx)["turn"] != 4 {
+		t.Errorf("unexpected extras %v", errors.ExtrasFromContext(ctx))
+	}
+
+	// The maps returned are copies
+	tags["call_uuid"] = "5"
+	if errors.TagsFromContext(ctx)["call_uuid"] != "1" {
+		t.Error("expected the tags of the context to be untouched")
+	}
+
+	if errors.TagsFromContext(context.Background()) != nil {
+		t.Error("expected no tags")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := errors.ContextWithTags(context.Background(), map[string]string{"call_uuid": "1", "vendor": "azure"})
+	ctx = errors.ContextWithExtras(ctx, map[string]interface{}{"turn": 4})
+
+	err := errors.NewErrorWithTags("failed", nil, true, map[string]string{"vendor": "google"})
+	enriched := errors.FromContext(ctx, err)
+
+	tags := errors.Tags(enriched)
+	if tags["call_uuid"] != "1" {
+		t.Errorf("expected the tags of the context, got %v", tags)
+	}
+	if tags["vendor"] != "google" {
+		t.Errorf("expected the tags of the error to take precedence, got %v", tags)
+	}
+	if errors.Extras(enriched)["turn"] != 4 {
+		t.Errorf("unexpected extras %v", errors.Extras(enriched))
+	}
+	if !errors.Fatal(enriched) {
+		t.Error("expected the error to remain fatal")
+	}
+
+	if errors.FromContext(ctx, nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+	if errors.FromContext(context.Background(), err) != err {
+		t.Error("expected the error as is for a context without metadata")
+	}
+}
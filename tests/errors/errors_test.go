@@ -0,0 +1,113 @@
+package tests
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestIsThroughRung(t *testing.T) {
+	root := stderrors.New("root")
+	err := errors.NewError("outer", fmt.Errorf("middle: %w", root), false)
+
+	if !errors.Is(err, root) {
+		t.Error("expected errors.Is to find the root through a rung")
+	}
+	if !stderrors.Is(err, root) {
+		t.Error("expected stdlib errors.Is to find the root through a rung")
+	}
+}
+
+func TestAsThroughRung(t *testing.T) {
+	var target *customError
+	err := errors.NewError("outer", fmt.Errorf("middle: %w", &customError{"root"}), false)
+
+	if !errors.As(err, &target) || target.msg != "root" {
+		t.Errorf("expected errors.As to find *customError, got %v", target)
+	}
+}
+
+func TestFatalMixedChain(t *testing.T) {
+	fatal := errors.NewError("fatal", nil, true)
+	err := fmt.Errorf("wrapped: %w", fatal)
+
+	if !errors.Fatal(err) {
+		t.Error("expected a fatal rung wrapped with %w to be fatal")
+	}
+	if errors.Fatal(fmt.Errorf("wrapped: %w", stderrors.New("plain"))) {
+		t.Error("expected a chain without rungs not to be fatal")
+	}
+}
+
+func TestTagsMixedChain(t *testing.T) {
+	inner := errors.NewErrorWithTags("inner", nil, false, map[string]string{"a": "inner", "b": "inner"})
+	outer := errors.NewErrorWithTags("outer", fmt.Errorf("middle: %w", inner), false, map[string]string{"a": "outer"})
+
+	tags := errors.Tags(fmt.Errorf("top: %w", outer))
+	if tags["a"] != "outer" || tags["b"] != "inner" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
+
+func TestExtrasMixedChain(t *testing.T) {
+	inner := errors.NewErrorWithExtras("inner", nil, false, map[string]interface{}{"count": 1})
+	err := fmt.Errorf("top: %w", inner)
+
+	if extras := errors.Extras(err); extras["count"] != 1 {
+		t.Errorf("unexpected extras %v", extras)
+	}
+}
+
+func TestIgnoreMixedChain(t *testing.T) {
+	err := errors.NewError("outer", fmt.Errorf("middle: %w", errors.NewErrorToIgnore("ignore", nil)), false)
+
+	if !errors.Ignore(err) {
+		t.Error("expected the chain to be ignored")
+	}
+}
+
+func TestCodeMixedChain(t *testing.T) {
+	err := fmt.Errorf("top: %w", errors.NewErrorWithCode("not found", http.StatusNotFound, nil))
+
+	if code := errors.Code(err, 0); code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestMultiErrorTree(t *testing.T) {
+	err := stderrors.Join(
+		stderrors.New("plain"),
+		fmt.Errorf("wrapped: %w", errors.NewErrorWithCode("conflict", http.StatusConflict, nil)),
+		errors.NewErrorWithTags("tagged", nil, true, map[string]string{"k": "v"}),
+	)
+
+	if !errors.Fatal(err) {
+		t.Error("expected the tree to be fatal")
+	}
+	if code := errors.Code(err, 0); code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, code)
+	}
+	if tags := errors.Tags(err); tags["k"] != "v" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
+
+func TestDeepestCauseMixedChain(t *testing.T) {
+	root := stderrors.New("root")
+	err := errors.NewError("outer", fmt.Errorf("middle: %w", root), false)
+
+	if cause := errors.DeepestCause(err); cause != root {
+		t.Errorf("expected the root error, got %v", cause)
+	}
+}
+
+type customError struct {
+	msg string
+}
+
+func (e *customError) Error() string {
+	return e.msg
+}
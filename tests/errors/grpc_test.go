@@ -0,0 +1,83 @@
+package tests
+
+import (
+	stderrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	err := errors.New("invalid phone number",
+		errors.WithKind(errors.InvalidArgument),
+		errors.WithFatal(true),
+		errors.WithTags(map[string]string{"call_uuid": "1"}),
+		errors.WithExtras(map[string]interface{}{"attempt": 2}),
+		errors.WithRetryAfter(time.Second),
+		errors.WithFieldViolation("phone", "must be 10 digits"),
+	)
+
+	st := errors.ToGRPCStatus(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected %v, got %v", codes.InvalidArgument, st.Code())
+	}
+
+	rebuilt := errors.FromGRPCStatus(st)
+	if status.Code(rebuilt) != codes.InvalidArgument {
+		t.Errorf("expected the rebuilt error to keep its status, got %v", status.Code(rebuilt))
+	}
+	if !errors.Is(rebuilt, errors.InvalidArgument) {
+		t.Error("expected the rebuilt error to be of kind InvalidArgument")
+	}
+	if !errors.Fatal(rebuilt) {
+		t.Error("expected the rebuilt error to be fatal")
+	}
+	if code := errors.Code(rebuilt, 0); code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, code)
+	}
+	if tags := errors.Tags(rebuilt); tags["call_uuid"] != "1" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if extras := errors.Extras(rebuilt); extras["attempt"] != float64(2) {
+		t.Errorf("unexpected extras %v", extras)
+	}
+	if violations := errors.FieldViolations(rebuilt); len(violations) != 1 || violations[0].Field != "phone" {
+		t.Errorf("unexpected field violations %v", violations)
+	}
+}
+
+func TestFromGRPCStatusWithoutDetails(t *testing.T) {
+	err := errors.FromGRPCStatus(status.New(codes.Unavailable, "down"))
+
+	if !errors.Is(err, errors.Unavailable) {
+		t.Error("expected the error to be of kind Unavailable")
+	}
+	if errors.FromGRPCStatus(status.New(codes.OK, "")) != nil {
+		t.Error("expected nil for an OK status")
+	}
+}
+
+func TestToGRPCStatusMessage(t *testing.T) {
+	cause := errors.NewError("pq: password authentication failed for user \"vcore\"", nil, false)
+	err := errors.NewErrorWithKind("user lookup failed", errors.Unavailable, cause)
+
+	if st := errors.ToGRPCStatus(err); st.Message() != "user lookup failed" {
+		t.Errorf("expected only the topmost message to be sent, got %q", st.Message())
+	}
+	if st := errors.ToGRPCStatus(stderrors.New("dial tcp: connection refused")); st.Message() != st.Code().String() {
+		t.Errorf("expected the message of an error without one of its own to be the code, got %q", st.Message())
+	}
+}
+
+func TestToGRPCStatusWrapped(t *testing.T) {
+	remote := errors.FromGRPCStatus(status.New(codes.NotFound, "call not found"))
+	err := errors.NewError("fetching the call", remote, false)
+
+	if st := errors.ToGRPCStatus(err); st.Code() != codes.NotFound || st.Message() != "call not found" {
+		t.Errorf("expected the status of the cause, got %v", st)
+	}
+}
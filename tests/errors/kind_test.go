@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"google.golang.org/grpc/codes"
+)
+
+func TestKindIs(t *testing.T) {
+	err := errors.NewError("outer", errors.NewErrorWithKind("missing", errors.NotFound, nil), false)
+
+	if !errors.Is(err, errors.NotFound) {
+		t.Error("expected the error to be of kind NotFound")
+	}
+	if errors.Is(err, errors.Conflict) {
+		t.Error("did not expect the error to be of kind Conflict")
+	}
+	if kind := errors.KindOf(err); kind != errors.NotFound {
+		t.Errorf("expected NotFound, got %v", kind)
+	}
+}
+
+func TestKindCodes(t *testing.T) {
+	err := errors.NewErrorWithKind("unavailable", errors.Unavailable, nil)
+
+	if code := errors.Code(err, 0); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if code := errors.GRPCCode(err); code != codes.Unavailable {
+		t.Errorf("expected %v, got %v", codes.Unavailable, code)
+	}
+}
+
+func TestExplicitCodeOverridesKind(t *testing.T) {
+	err := errors.NewErrorWithCode("teapot", http.StatusTeapot, errors.NewErrorWithKind("missing", errors.NotFound, nil))
+
+	if code := errors.Code(err, 0); code != http.StatusTeapot {
+		t.Errorf("expected %d, got %d", http.StatusTeapot, code)
+	}
+}
+
+func TestKindAsWrappedSentinel(t *testing.T) {
+	err := fmt.Errorf("lookup failed: %w", errors.Timeout)
+
+	if code := errors.Code(err, 0); code != http.StatusGatewayTimeout {
+		t.Errorf("expected %d, got %d", http.StatusGatewayTimeout, code)
+	}
+	if code := errors.GRPCCode(err); code != codes.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", codes.DeadlineExceeded, code)
+	}
+}
+
+func TestGRPCCodeFromHTTPCode(t *testing.T) {
+	if code := errors.GRPCCode(errors.NewErrorWithCode("bad", http.StatusBadRequest, nil)); code != codes.InvalidArgument {
+		t.Errorf("expected %v, got %v", codes.InvalidArgument, code)
+	}
+	if code := errors.GRPCCode(errors.NewError("plain", nil, false)); code != codes.Internal {
+		t.Errorf("expected %v, got %v", codes.Internal, code)
+	}
+}
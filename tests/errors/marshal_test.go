@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	root := fmt.Errorf("dial failed: %w", errors.Timeout)
+	inner := errors.NewErrorWithTags("vendor unreachable", root, true, map[string]string{"vendor": "google"})
+	err := errors.New("could not transcribe",
+		errors.WithCause(inner),
+		errors.WithCode(http.StatusBadGateway),
+		errors.WithExtras(map[string]interface{}{"attempt": "2"}),
+	)
+
+	data, marshalErr := errors.Marshal(err)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+
+	var document struct {
+		Chain []struct {
+			Type    string         `json:"type"`
+			Message string         `json:"message"`
+			Stack   []errors.Frame `json:"stack"`
+		} `json:"chain"`
+	}
+	if jsonErr := json.Unmarshal(data, &document); jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	if len(document.Chain) != 4 || document.Chain[0].Type != "rung" || len(document.Chain[0].Stack) == 0 {
+		t.Errorf("unexpected document %s", data)
+	}
+
+	var rebuilt error
+	if unmarshalErr := errors.Unmarshal(data, &rebuilt); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+
+	if rebuilt.Error() != err.Error() {
+		t.Errorf("expected message %q, got %q", err.Error(), rebuilt.Error())
+	}
+	if errors.Fatal(rebuilt) != errors.Fatal(err) || !errors.Fatal(errors.Unwrap(errors.Unwrap(rebuilt))) {
+		t.Error("expected the rebuilt error to keep the fatal flags")
+	}
+	if code := errors.Code(rebuilt, 0); code != http.StatusBadGateway {
+		t.Errorf("expected %d, got %d", http.StatusBadGateway, code)
+	}
+	if !errors.Is(rebuilt, errors.Timeout) {
+		t.Error("expected the rebuilt error to be of kind Timeout")
+	}
+	if tags := errors.Tags(rebuilt); tags["vendor"] != "google" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if extras := errors.Extras(rebuilt); extras["attempt"] != "2" {
+		t.Errorf("unexpected extras %v", extras)
+	}
+	if errors.Stacktrace(rebuilt) != errors.Stacktrace(err) {
+		t.Errorf("expected the same stacktrace, got\n%s\ninstead of\n%s", errors.Stacktrace(rebuilt), errors.Stacktrace(err))
+	}
+}
+
+func TestMarshalNil(t *testing.T) {
+	data, err := errors.Marshal(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt := fmt.Errorf("not nil")
+	if err := errors.Unmarshal(data, &rebuilt); err != nil || rebuilt != nil {
+		t.Errorf("expected a nil error, got %v", rebuilt)
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestMultiErrorOrNil(t *testing.T) {
+	var errs errors.Multi
+	errs.Append(nil)
+
+	if errs.ErrorOrNil() != nil {
+		t.Error("expected nil when no error was collected")
+	}
+}
+
+func TestMultiConcurrentAppend(t *testing.T) {
+	var errs errors.Multi
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs.Append(errors.NewError("failed", nil, false))
+		}()
+	}
+	wg.Wait()
+
+	if errs.Len() != 50 {
+		t.Errorf("expected 50 errors, got %d", errs.Len())
+	}
+}
+
+func TestMultiSemantics(t *testing.T) {
+	notFound := errors.NewErrorWithKind("missing", errors.NotFound, nil)
+	var errs errors.Multi
+	errs.Append(
+		errors.NewErrorWithTags("first", nil, false, map[string]string{"k": "first"}),
+		errors.NewErrorWithCode("unavailable", http.StatusServiceUnavailable, nil),
+		notFound,
+		errors.NewErrorWithTags("fatal", nil, true, map[string]string{"k": "last", "other": "v"}),
+	)
+	err := errs.ErrorOrNil()
+
+	if !errors.Fatal(err) {
+		t.Error("expected the collection to be fatal")
+	}
+	if code := errors.Code(err, 0); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if tags := errors.Tags(err); tags["k"] != "first" || tags["other"] != "v" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if errors.Ignore(err) {
+		t.Error("did not expect the collection to be ignored")
+	}
+	if !errors.Is(err, notFound) || !errors.Is(err, errors.NotFound) {
+		t.Error("expected errors.Is to look into the collection")
+	}
+	if groups := errs.GroupByCode(0); len(groups[http.StatusInternalServerError]) != 2 || len(groups[http.StatusNotFound]) != 1 {
+		t.Errorf("unexpected groups %v", groups)
+	}
+}
+
+func TestMultiIgnore(t *testing.T) {
+	var errs errors.Multi
+	errs.Append(errors.NewErrorToIgnore("a", nil), errors.NewErrorToIgnore("b", nil))
+
+	if !errors.Ignore(errs.ErrorOrNil()) {
+		t.Error("expected the collection to be ignored when all errors are")
+	}
+
+	errs.Append(errors.NewError("c", nil, false))
+	if errors.Ignore(errs.ErrorOrNil()) {
+		t.Error("did not expect the collection to be ignored")
+	}
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	cause := errors.NewError("cause", nil, false)
+	tags := map[string]string{"call_uuid": "1"}
+	err := errors.New("failed",
+		errors.WithCause(cause),
+		errors.WithFatal(true),
+		errors.WithCode(http.StatusBadGateway),
+		errors.WithTags(tags),
+	)
+
+	if !errors.Fatal(err) {
+		t.Error("expected the error to be fatal")
+	}
+	if code := errors.Code(err, 0); code != http.StatusBadGateway {
+		t.Errorf("expected %d, got %d", http.StatusBadGateway, code)
+	}
+	if errors.DeepestCause(err).Error() != "cause" {
+		t.Errorf("unexpected cause %v", errors.DeepestCause(err))
+	}
+
+	// The tags passed in are copied
+	tags["call_uuid"] = "2"
+	if got := errors.Tags(err)["call_uuid"]; got != "1" {
+		t.Errorf("expected the tags to be copied, got %s", got)
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestProblemResponder(t *testing.T) {
+	responder := &errors.ProblemResponder{
+		TypeBaseURI: "https://errors.skit.ai/",
+		PublicTags:  []string{"call_uuid"},
+	}
+	handler := responder.HandleFunc(func(w http.ResponseWriter, r *http.Request) error {
+		cause := errors.NewError("db password rejected", nil, false)
+		return errors.New("user not found",
+			errors.WithCause(cause),
+			errors.WithKind(errors.NotFound),
+			errors.WithTags(map[string]string{"call_uuid": "1", "db_host": "internal"}),
+			errors.WithExtras(map[string]interface{}{"query": "select"}),
+		)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+	if ct := recorder.Header().Get("Content-Type"); ct != errors.ProblemContentType {
+		t.Errorf("unexpected content type %s", ct)
+	}
+
+	var problem map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem["type"] != "https://errors.skit.ai/not_found" || problem["detail"] != "user not found" || problem["instance"] != "/users/1" {
+		t.Errorf("unexpected problem %v", problem)
+	}
+	if tags := problem["tags"].(map[string]interface{}); len(tags) != 1 || tags["call_uuid"] != "1" {
+		t.Errorf("expected only the public tags, got %v", tags)
+	}
+	if _, ok := problem["extras"]; ok {
+		t.Error("did not expect the extras to be written")
+	}
+}
+
+func TestProblemResponderStatus(t *testing.T) {
+	cases := []struct {
+		name      string
+		responder *errors.ProblemResponder
+		err       error
+		expected  int
+	}{
+		{"application code", &errors.ProblemResponder{}, errors.NewErrorWithCode("quota exhausted", 1001, nil), http.StatusInternalServerError},
+		{"application code with a kind", &errors.ProblemResponder{},
+			errors.New("quota exhausted", errors.WithCode(1001), errors.WithKind(errors.RateLimited)), http.StatusTooManyRequests},
+		{"application code with a default", &errors.ProblemResponder{DefaultCode: http.StatusBadGateway},
+			errors.NewErrorWithCode("quota exhausted", 1001, nil), http.StatusBadGateway},
+		{"success code", &errors.ProblemResponder{}, errors.NewErrorWithCode("moved", http.StatusFound, nil), http.StatusInternalServerError},
+		{"error code", &errors.ProblemResponder{}, errors.NewErrorWithCode("conflict", http.StatusConflict, nil), http.StatusConflict},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		c.responder.Write(recorder, httptest.NewRequest(http.MethodGet, "/", nil), c.err)
+
+		var problem errors.Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if recorder.Code != c.expected || problem.Status != c.expected || problem.Title != http.StatusText(c.expected) {
+			t.Errorf("%s: expected %d, got %d with the problem %+v", c.name, c.expected, recorder.Code, problem)
+		}
+	}
+}
+
+func TestProblemResponderCanceled(t *testing.T) {
+	problem := (&errors.ProblemResponder{}).Problem(nil, errors.NewErrorWithKind("client went away", errors.Canceled, nil))
+
+	if problem.Status != 499 || problem.Title != "Client Closed Request" {
+		t.Errorf("expected 499 with a title, got %+v", problem)
+	}
+}
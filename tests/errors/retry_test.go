@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/errors"
+)
+
+var testRetryPolicy = errors.RetryPolicy{
+	InitialInterval: time.Millisecond,
+	Multiplier:      2,
+	MaxAttempts:     3,
+}
+
+func TestRetryUntilSuccess(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), testRetryPolicy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.NewErrorWithKind("vendor down", errors.Unavailable, nil)
+		}
+		return nil
+	})
+
+	if err != nil || calls != 3 {
+		t.Errorf("expected success after 3 calls, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryStopsOnFatal(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), testRetryPolicy, func(context.Context) error {
+		calls++
+		return errors.NewError("bad credentials", nil, true)
+	})
+
+	if calls != 1 {
+		t.Errorf("expected a single call, got %d", calls)
+	}
+	if !errors.Fatal(err) {
+		t.Error("expected the returned error to stay fatal")
+	}
+	if attempts := errors.Extras(err)["retry_attempts"]; attempts != 1 {
+		t.Errorf("expected the attempts to be recorded, got %v", attempts)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), testRetryPolicy, func(context.Context) error {
+		calls++
+		return errors.NewError("timeout", nil, false)
+	})
+
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if messages := errors.Extras(err)["retry_errors"].([]string); len(messages) != 3 {
+		t.Errorf("expected 3 recorded errors, got %v", messages)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := errors.Retry(ctx, testRetryPolicy, func(context.Context) error {
+		return errors.NewError("timeout", nil, false)
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error to wrap context.Canceled, got %v", err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.NewError("fatal", nil, true), false},
+		{errors.NewErrorToIgnore("ignore", nil), false},
+		{errors.NewErrorWithKind("down", errors.Unavailable, nil), true},
+		{errors.NewErrorWithKind("missing", errors.NotFound, nil), false},
+		{errors.New("slow down", errors.WithRetryAfter(time.Second)), true},
+		{context.Canceled, false},
+		{errors.NewError("plain", nil, false), true},
+	}
+
+	for _, c := range cases {
+		if got := errors.Retryable(c.err); got != c.retryable {
+			t.Errorf("Retryable(%v): expected %v, got %v", c.err, c.retryable, got)
+		}
+	}
+}
+
+func TestNetworkRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{errors.NewError("dial failed", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, false), true},
+		{&net.DNSError{Err: "no such host", Name: "redis.invalid", IsNotFound: true}, false},
+		{errors.NewErrorWithKind("down", errors.Unavailable, nil), true},
+		{errors.NewError("invalid uri", nil, false), false},
+		{errors.NewError("fatal", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true), false},
+	}
+
+	for _, c := range cases {
+		if got := errors.NetworkRetryable(c.err); got != c.retryable {
+			t.Errorf("NetworkRetryable(%v): expected %v, got %v", c.err, c.retryable, got)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	_err "github.com/pkg/errors"
+	"github.com/skit-ai/vcore/errors"
+)
+
+func TestStackStartsAtCaller(t *testing.T) {
+	for name, err := range map[string]error{
+		"New":      errors.New("failed"),
+		"NewError": errors.NewError("failed", nil, false),
+	} {
+		frames := errors.Frames(err)
+		if len(frames) == 0 {
+			t.Fatalf("%s: expected a stack", name)
+		}
+		if !strings.HasSuffix(frames[0].Function, "TestStackStartsAtCaller") {
+			t.Errorf("%s: expected the stack to start at the caller, got %s", name, frames[0].Function)
+		}
+	}
+}
+
+func TestStackModes(t *testing.T) {
+	if frames := errors.Frames(errors.New("failed", errors.WithoutStack())); frames != nil {
+		t.Errorf("expected no stack, got %v", frames)
+	}
+	if frames := errors.Frames(errors.New("failed", errors.WithStackMode(errors.StackCaller))); len(frames) != 1 {
+		t.Errorf("expected a single frame, got %v", frames)
+	}
+	if frames := errors.Frames(errors.New("failed", errors.WithStackDepth(2))); len(frames) != 2 {
+		t.Errorf("expected 2 frames, got %v", frames)
+	}
+
+	errors.SetStackMode(errors.StackNone)
+	defer errors.SetStackMode(errors.StackFull)
+	if frames := errors.Frames(errors.NewError("failed", nil, false)); frames != nil {
+		t.Errorf("expected no stack, got %v", frames)
+	}
+	if frames := errors.Frames(errors.New("failed", errors.WithStackMode(errors.StackFull))); frames == nil {
+		t.Error("expected the option to override the package level mode")
+	}
+}
+
+func TestFrameFilters(t *testing.T) {
+	err := errors.New("failed")
+
+	errors.SetFrameFilters(errors.DropStdlib())
+	defer errors.SetFrameFilters()
+
+	for _, f := range errors.Frames(err) {
+		if strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, "testing.") {
+			t.Errorf("expected stdlib frames to be dropped, got %s", f.Function)
+		}
+	}
+	if strings.Contains(errors.Stacktrace(err), "testing.tRunner") {
+		t.Error("expected Stacktrace to apply the filters")
+	}
+}
+
+func TestCaller(t *testing.T) {
+	helper := func() error {
+		return errors.New("failed", errors.WithStackSkip(1))
+	}
+
+	frame, ok := errors.Caller(helper())
+	if !ok {
+		t.Fatal("expected a caller")
+	}
+	if !strings.HasSuffix(frame.Function, "TestCaller") {
+		t.Errorf("expected the frame of the test, got %s", frame.Function)
+	}
+	if !strings.HasPrefix(frame.String(), frame.Function+" stack_test.go:") {
+		t.Errorf("unexpected compact frame %s", frame)
+	}
+
+	if _, ok := errors.Caller(fmt.Errorf("plain")); ok {
+		t.Error("expected no caller for an error without a stack")
+	}
+}
+
+func TestStackFormatting(t *testing.T) {
+	err := errors.New("failed")
+	if got := fmt.Sprintf("%v", err); got != "failed" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "TestStackFormatting") {
+		t.Errorf("expected %%+v to print the stack, got %q", got)
+	}
+}
+
+func BenchmarkNewStackFull(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("failed")
+	}
+}
+
+func BenchmarkNewStackDepth4(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("failed", errors.WithStackDepth(4))
+	}
+}
+
+func BenchmarkNewStackCaller(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("failed", errors.WithStackMode(errors.StackCaller))
+	}
+}
+
+func BenchmarkNewWithoutStack(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("failed", errors.WithoutStack())
+	}
+}
+
+func BenchmarkNewErrorPkgErrorsStack(b *testing.B) {
+	// The previous behaviour, where every error captured a stack through pkg/errors
+	for i := 0; i < b.N; i++ {
+		_ = _err.WithStack(errors.New("failed", errors.WithoutStack()))
+	}
+}
+
+func BenchmarkStacktrace(b *testing.B) {
+	err := errors.New("failed")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Stacktrace(err)
+	}
+}
+
+func BenchmarkStacktraceFiltered(b *testing.B) {
+	err := errors.New("failed")
+	errors.SetFrameFilters(errors.DropStdlib())
+	defer errors.SetFrameFilters()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Stacktrace(err)
+	}
+}
+
+func BenchmarkCaller(b *testing.B) {
+	err := errors.New("failed")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = errors.Caller(err)
+	}
+}
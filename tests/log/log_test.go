@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log"
+)
+
+func TestLoggersAreIndependent(t *testing.T) {
+	var first, second bytes.Buffer
+	debugLogger := log.New(log.WithLevel(log.DEBUG), log.WithWriter(&first))
+	errorLogger := log.New(log.WithLevel(log.WARN), log.WithWriter(&second))
+
+	errorLogger.SetLevel(log.ERROR)
+	if !debugLogger.IsDebug() {
+		t.Error("expected SetLevel to change only the level of its receiver")
+	}
+	if errorLogger.IsWarn() {
+		t.Error("expected SetLevel to change the level of its receiver")
+	}
+
+	debugLogger.Debug("debug message")
+	errorLogger.Warn("warn message")
+
+	if !strings.Contains(first.String(), "[DEBUG] debug message") {
+		t.Errorf("unexpected output %q", first.String())
+	}
+	if second.Len() != 0 {
+		t.Errorf("expected nothing to be logged, got %q", second.String())
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(log.WithWriter(&buffer), log.WithCaller(), log.WithTimeFormat(""))
+
+	logger.Warnf("retrying %s", "dial")
+	if got := buffer.String(); !strings.HasPrefix(got, "[WARN] log_test.go:") || !strings.HasSuffix(got, " retrying dial\n") {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(log.WithWriter(&buffer), log.WithFormat(log.FormatJSON), log.WithCaller())
+
+	logger.Errorf(errors.New("dial failed"), "could not connect to %s", "redis")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %s", buffer.String(), err)
+	}
+	if entry["level"] != "error" || entry["msg"] != "could not connect to redis" || entry["error"] != "dial failed" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "log_test.go:") {
+		t.Errorf("unexpected caller %v", entry["caller"])
+	}
+	if entry["time"] == nil || entry["stack"] == nil {
+		t.Errorf("expected a time and a stack, got %v", entry)
+	}
+}
+
+func TestSetLevelConcurrently(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(log.WithWriter(&buffer))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(level int) {
+			defer wg.Done()
+			logger.SetLevel(level)
+		}(i % (log.TRACE + 1))
+		go func() {
+			defer wg.Done()
+			logger.Info("message")
+		}()
+	}
+	wg.Wait()
+}
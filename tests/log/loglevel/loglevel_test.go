@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/log"
+	"github.com/skit-ai/vcore/log/loglevel"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+func restoreLevels(t *testing.T) {
+	initial := loglevel.Current()
+	t.Cleanup(func() {
+		_ = loglevel.Set(initial)
+	})
+}
+
+func serve(t *testing.T, method, body string) (*httptest.ResponseRecorder, loglevel.Levels) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	loglevel.Handler().ServeHTTP(recorder, httptest.NewRequest(method, loglevel.Path, strings.NewReader(body)))
+
+	var levels loglevel.Levels
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), &levels); err != nil {
+			t.Fatalf("unexpected body %q: %s", recorder.Body.String(), err)
+		}
+	}
+	return recorder, levels
+}
+
+func TestHandler(t *testing.T) {
+	restoreLevels(t)
+
+	if _, levels := serve(t, http.MethodGet, ""); levels != loglevel.Current() {
+		t.Errorf("expected the current levels, got %v", levels)
+	}
+
+	_, levels := serve(t, http.MethodPut, `{"level": "debug"}`)
+	if levels != (loglevel.Levels{Log: "debug", Slog: "debug"}) || log.Level() != log.DEBUG || slog.Level() != "debug" {
+		t.Errorf("expected both the levels to be debug, got %v", levels)
+	}
+
+	_, levels = serve(t, http.MethodPut, `{"slog": "error"}`)
+	if levels != (loglevel.Levels{Log: "debug", Slog: "error"}) {
+		t.Errorf("expected only the slog level to change, got %v", levels)
+	}
+
+	_, levels = serve(t, http.MethodPut, `{"slog_named": "transport.amqp=debug"}`)
+	defer func() { _ = slog.SetLevels("") }()
+	if levels.SlogNamed != "transport.amqp=debug" || levels.Slog != "error" {
+		t.Errorf("expected only the named levels to change, got %v", levels)
+	}
+
+	if recorder, _ := serve(t, http.MethodPut, `{"log": "warn", "slog": "trace"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d for an unknown level, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if log.Level() != log.DEBUG {
+		t.Error("expected no level to change when one of them is unknown")
+	}
+
+	if recorder, _ := serve(t, http.MethodPost, ""); recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected %d, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	restoreLevels(t)
+	if err := loglevel.Set(loglevel.Levels{Log: "warn", Slog: "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loglevel.HandleSignals(ctx)
+
+	waitFor := func(expected loglevel.Levels) {
+		t.Helper()
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if loglevel.Current() == expected {
+				return
+			}
+		}
+		t.Fatalf("expected %v, got %v", expected, loglevel.Current())
+	}
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitFor(loglevel.Levels{Log: "info", Slog: "debug"})
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitFor(loglevel.Levels{Log: "debug", Slog: "error"})
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitFor(loglevel.Levels{Log: "warn", Slog: "info"})
+}
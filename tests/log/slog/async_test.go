@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// gatedWriter is a writer whose writes wait for the gate to be opened
+type gatedWriter struct {
+	output
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	return w.output.Write(p)
+}
+
+// writeLines writes the first line and waits for it to be picked up by the
+// writer, so that the rest of the lines fill the buffer
+func writeLines(t *testing.T, a *slog.AsyncWriter, w *gatedWriter, lines ...string) {
+	t.Helper()
+	_, _ = a.Write([]byte(lines[0]))
+	<-w.started
+	for _, line := range lines[1:] {
+		_, _ = a.Write([]byte(line))
+	}
+}
+
+func TestAsyncWriterDropPolicies(t *testing.T) {
+	for policy, expected := range map[slog.DropPolicy]string{
+		slog.DropNewest: "1\n2\n3\n",
+		slog.DropOldest: "1\n3\n4\n",
+	} {
+		w := newGatedWriter()
+		a := slog.NewAsyncWriter(w, 2, policy)
+		writeLines(t, a, w, "1\n", "2\n", "3\n", "4\n")
+
+		close(w.gate)
+		_ = a.Close()
+		if w.String() != expected || a.Dropped() != 1 {
+			t.Errorf("%s: expected %q with a line dropped, got %q with %d", policy, expected, w.String(), a.Dropped())
+		}
+	}
+}
+
+func TestAsyncWriterBlocks(t *testing.T) {
+	w := newGatedWriter()
+	a := slog.NewAsyncWriter(w, 1, slog.Block)
+	writeLines(t, a, w, "1\n", "2\n")
+
+	written := make(chan struct{})
+	go func() {
+		_, _ = a.Write([]byte("3\n"))
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("expected the write to wait for the buffer to have room")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(w.gate)
+	<-written
+	_ = a.Close()
+	if w.String() != "1\n2\n3\n" || a.Dropped() != 0 {
+		t.Errorf("expected every line to be written in order, got %q", w.String())
+	}
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	w := newGatedWriter()
+	a := slog.NewAsyncWriter(w, 10, slog.Block)
+	writeLines(t, a, w, "1\n", "2\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := a.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the flush to time out, got %v", err)
+	}
+
+	close(w.gate)
+	if err := a.Flush(context.Background()); err != nil || w.String() != "1\n2\n" {
+		t.Errorf("expected the lines to be flushed, got %q, %v", w.String(), err)
+	}
+	_ = a.Close()
+
+	if _, _ = a.Write([]byte("3\n")); w.String() != "1\n2\n3\n" {
+		t.Errorf("expected the lines to be written synchronously once closed, got %q", w.String())
+	}
+}
+
+func TestAsyncWriterFlushTimeouts(t *testing.T) {
+	w := newGatedWriter()
+	a := slog.NewAsyncWriter(w, 10, slog.Block)
+	writeLines(t, a, w, "1\n", "2\n")
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		if err := a.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the flush to time out, got %v", err)
+		}
+		cancel()
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected the flushes which timed out not to leave goroutines behind, got %d more", n-goroutines)
+	}
+
+	close(w.gate)
+	_ = a.Close()
+}
+
+func TestSetAsync(t *testing.T) {
+	o := captureOutput(t)
+	slog.SetAsync(16, slog.DropNewest)
+	t.Cleanup(func() { slog.SetAsync(0, slog.Block) })
+
+	slog.Info("logged asynchronously")
+	if err := slog.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if line := o.String(); !strings.Contains(line, `msg="logged asynchronously"`) || !strings.Contains(line, "caller=async_test.go:") {
+		t.Errorf("unexpected line %q", line)
+	}
+}
+
+func TestParseDropPolicy(t *testing.T) {
+	for _, policy := range []slog.DropPolicy{slog.Block, slog.DropOldest, slog.DropNewest} {
+		if parsed, err := slog.ParseDropPolicy(policy.String()); err != nil || parsed != policy {
+			t.Errorf("expected %s to be parsed, got %s, %v", policy, parsed, err)
+		}
+	}
+	if _, err := slog.ParseDropPolicy("drop-all"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
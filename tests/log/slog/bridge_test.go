@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	stdslog "log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder is a Logger recording the lines logged
+type recorder struct {
+	fields map[string]any
+	lines  *[]line
+}
+
+type line struct {
+	level  string
+	err    error
+	msg    string
+	args   []any
+	fields map[string]any
+}
+
+func newRecorder() *recorder {
+	return &recorder{lines: new([]line)}
+}
+
+func (r *recorder) record(level string, err error, msg string, args ...any) {
+	*r.lines = append(*r.lines, line{level: level, err: err, msg: msg, args: args, fields: r.fields})
+}
+
+func (r *recorder) Info(msg string, args ...any)  { r.record("info", nil, msg, args...) }
+func (r *recorder) Warn(msg string, args ...any)  { r.record("warn", nil, msg, args...) }
+func (r *recorder) Debug(msg string, args ...any) { r.record("debug", nil, msg, args...) }
+func (r *recorder) Error(err error, msg string, args ...any) {
+	r.record("error", err, msg, args...)
+}
+func (r *recorder) Infof(format string, args ...any)  { r.Info(fmt.Sprintf(format, args...)) }
+func (r *recorder) Warnf(format string, args ...any)  { r.Warn(fmt.Sprintf(format, args...)) }
+func (r *recorder) Debugf(format string, args ...any) { r.Debug(fmt.Sprintf(format, args...)) }
+func (r *recorder) Errorf(err error, format string, args ...any) {
+	r.Error(err, fmt.Sprintf(format, args...))
+}
+func (r *recorder) InfoContext(ctx context.Context, msg string, args ...any) {
+	r.WithTraceId(ctx).Info(msg, args...)
+}
+func (r *recorder) WarnContext(ctx context.Context, msg string, args ...any) {
+	r.WithTraceId(ctx).Warn(msg, args...)
+}
+func (r *recorder) DebugContext(ctx context.Context, msg string, args ...any) {
+	r.WithTraceId(ctx).Debug(msg, args...)
+}
+func (r *recorder) ErrorContext(ctx context.Context, err error, msg string, args ...any) {
+	r.WithTraceId(ctx).Error(err, msg, args...)
+}
+func (r *recorder) WithTraceId(ctx context.Context) slog.Logger {
+	return r.WithFields(map[string]any{"trace_id": trace.SpanContextFromContext(ctx).TraceID().String()})
+}
+func (r *recorder) WithFields(fields map[string]any) slog.Logger {
+	merged := map[string]any{}
+	for k, v := range r.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &recorder{fields: merged, lines: r.lines}
+}
+func (r *recorder) WithSensitive(bool) slog.Logger { return r }
+func (r *recorder) SetSensitive(bool)              {}
+
+func contextWithSpan() (context.Context, trace.TraceID) {
+	traceID := trace.TraceID{1, 2, 3}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{4}})
+	return trace.ContextWithSpanContext(context.Background(), spanContext), traceID
+}
+
+func TestHandler(t *testing.T) {
+	r := newRecorder()
+	logger := stdslog.New(slog.NewHandler(r)).With("service", "asr").WithGroup("request")
+
+	logger.Debug("received", "id", 1, stdslog.Group("audio", "codec", "opus"))
+	logger.Warn("slow", stdslog.Group("", "ms", 120))
+	stdslog.New(slog.NewHandler(r)).Error("failed", "err", errors.New("timeout"), "id", 2)
+
+	ctx, traceID := contextWithSpan()
+	logger.InfoContext(ctx, "traced")
+
+	lines := *r.lines
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %v", lines)
+	}
+
+	if l := lines[0]; l.level != "debug" || l.msg != "received" || l.fields["service"] != "asr" ||
+		fmt.Sprint(l.args) != "[request.id 1 request.audio.codec opus]" {
+		t.Errorf("unexpected line %+v", l)
+	}
+	if l := lines[1]; l.level != "warn" || fmt.Sprint(l.args) != "[request.ms 120]" {
+		t.Errorf("unexpected line %+v", l)
+	}
+	if l := lines[2]; l.level != "error" || l.err == nil || l.err.Error() != "timeout" || fmt.Sprint(l.args) != "[id 2]" {
+		t.Errorf("expected the error to be passed to Error, got %+v", l)
+	}
+	if l := lines[3]; l.level != "info" || l.fields["trace_id"] != traceID.String() {
+		t.Errorf("expected the trace_id of the context, got %+v", l)
+	}
+}
+
+func TestFromStdLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.FromStdLogger(stdslog.New(stdslog.NewJSONHandler(&buffer, &stdslog.HandlerOptions{
+		AddSource: true,
+		Level:     stdslog.LevelDebug,
+	})))
+
+	decode := func() map[string]any {
+		t.Helper()
+		var entry map[string]any
+		if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+			t.Fatalf("unexpected output %q: %s", buffer.String(), err)
+		}
+		buffer.Reset()
+		return entry
+	}
+
+	ctx, traceID := contextWithSpan()
+	logger.WithFields(map[string]any{"service": "asr"}).WithTraceId(ctx).Errorf(errors.New("timeout"), "call %d failed", 7)
+	entry := decode()
+	if entry["level"] != "ERROR" || entry["msg"] != "call 7 failed" || entry["error"] != "timeout" ||
+		entry["service"] != "asr" || entry["trace_id"] != traceID.String() {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if source, _ := entry["source"].(map[string]any); source == nil || filepath.Base(source["file"].(string)) != "bridge_test.go" {
+		t.Errorf("expected the source to be the caller, got %v", entry["source"])
+	}
+
+	logger.WithSensitive(true).Debug("transcript", "text", "secret")
+	if entry := decode(); entry["text"] != nil || entry["msg"] != "transcript" {
+		t.Errorf("expected the args to be left out, got %v", entry)
+	}
+}
+
+func TestHandlerKeepsTheOrderOfAttrs(t *testing.T) {
+	o := captureOutput(t)
+	logger := stdslog.New(slog.Handler()).With("service", "asr", "call_uuid", "1", "attempt", 2, "vendor", "google")
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("slow")
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(o.String()), "\n") {
+		if !strings.Contains(line, "service=asr call_uuid=1 attempt=2 vendor=google") {
+			t.Errorf("expected the attributes in their order, got %s", line)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	stdslog "log/slog"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+func TestContextMethods(t *testing.T) {
+	o := captureOutput(t)
+
+	ctx, traceID := contextWithSpan()
+	ctx = slog.ContextWith(ctx, map[string]any{"call_uuid": "c1"})
+	ctx = slog.ContextWith(ctx, map[string]any{"turn": 3})
+	ctx = errors.ContextWithTags(ctx, map[string]string{"flow": "billing"})
+
+	slog.NewLogger().WarnContext(ctx, "slow response", "duration_ms", 1200)
+	line := o.String()
+	for _, field := range []string{
+		"trace_id=" + traceID.String(), "span_id=0400000000000000", "call_uuid=c1", "turn=3", "flow=billing",
+		`msg="slow response"`, "duration_ms=1200", "caller=context_test.go:",
+	} {
+		if !strings.Contains(line, field) {
+			t.Errorf("expected %s to be logged, got %s", field, line)
+		}
+	}
+
+	o.Reset()
+	slog.ErrorContext(ctx, errors.New("timeout"), "call failed")
+	if line := o.String(); !strings.Contains(line, "call_uuid=c1") || !strings.Contains(line, `msg="call failed" error=timeout`) ||
+		!strings.Contains(line, "caller=context_test.go:") {
+		t.Errorf("unexpected line %s", line)
+	}
+}
+
+func TestContextWithoutSpan(t *testing.T) {
+	o := captureOutput(t)
+
+	slog.InfoContext(context.Background(), "no span")
+	slog.WithTraceId(context.Background()).Info("no span")
+	if line := o.String(); strings.Contains(line, "trace_id") || strings.Contains(line, "span_id") {
+		t.Errorf("expected no trace fields for an invalid span context, got %s", line)
+	}
+}
+
+func TestContextWithSentryHub(t *testing.T) {
+	o := captureOutput(t)
+
+	hub := sentry.NewHub(nil, sentry.NewScope())
+	slog.InfoContext(sentry.SetHubOnContext(context.Background(), hub), "traced by sentry")
+
+	traceID, _, _ := strings.Cut(hub.GetTraceparent(), "-")
+	if line := o.String(); !strings.Contains(line, "sentry_trace_id="+traceID) {
+		t.Errorf("expected the trace id of the hub, got %s", line)
+	}
+}
+
+func TestHandlerLogsContextFields(t *testing.T) {
+	o := captureOutput(t)
+
+	ctx := slog.ContextWith(context.Background(), map[string]any{"call_uuid": "c1"})
+	stdslog.New(slog.Handler()).InfoContext(ctx, "from the standard library")
+	if line := o.String(); !strings.Contains(line, "call_uuid=c1") || !strings.Contains(line, "caller=context_test.go:") {
+		t.Errorf("expected the fields of the context, got %s", line)
+	}
+}
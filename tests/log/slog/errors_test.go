@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// decodeLines decodes the lines logged in the json format
+func decodeLines(t *testing.T, o *output) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(o.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("unexpected line %q: %s", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestErrorFields(t *testing.T) {
+	o := captureOutput(t)
+	setFormat(t, slog.FormatJSON)
+	slog.SetRedactionPolicy(slog.RedactionPolicy{Rules: []slog.RedactionRule{{Key: "phone", Mask: slog.KeepLast(4)}}})
+	defer slog.SetRedactionPolicy(slog.RedactionPolicy{})
+
+	err := errors.New("vendor timeout",
+		errors.WithCode(503),
+		errors.WithFatal(true),
+		errors.WithTags(map[string]string{"vendor": "asr"}),
+		errors.WithExtras(map[string]interface{}{"phone": "9876543210", "attempt": 3}),
+	)
+	slog.NewLogger().Error(fmt.Errorf("call failed: %w", err), "")
+	slog.NewLogger().WithSensitive(true).Error(err, "call failed", "attempt", 7)
+
+	entries := decodeLines(t, o)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 lines, got %v", entries)
+	}
+
+	entry := entries[0]
+	if entry["level"] != "error" || entry["error"] != "call failed: vendor timeout" || entry["error.code"] != float64(503) ||
+		entry["error.fatal"] != true || entry["error.tags.vendor"] != "asr" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry["error.extras.phone"] != "******3210" || entry["error.extras.attempt"] != float64(3) {
+		t.Errorf("expected the extras to be redacted as per the policy, got %v", entry)
+	}
+	if stack, _ := entry["error.stack"].([]any); len(stack) == 0 || !strings.Contains(fmt.Sprint(stack[0]), "errors_test.go") {
+		t.Errorf("expected the frames of the error, got %v", entry["error.stack"])
+	}
+
+	if entry := entries[1]; entry["msg"] != "call failed" || entry["attempt"] != nil || entry["error.extras.phone"] != nil || entry["error.tags.vendor"] != "asr" {
+		t.Errorf("expected the extras to be left out by a sensitive logger, got %v", entry)
+	}
+}
+
+func TestErrorFieldsOfPlainErrors(t *testing.T) {
+	o := captureOutput(t)
+	setFormat(t, slog.FormatJSON)
+
+	slog.Error(fmt.Errorf("timeout"), "call failed")
+	entry := decodeLines(t, o)[0]
+	for key := range entry {
+		if strings.HasPrefix(key, "error.") {
+			t.Errorf("expected no error fields for an error of the standard library, got %v", entry)
+		}
+	}
+}
+
+func TestIgnoredErrorsAreLoggedAtDebug(t *testing.T) {
+	o := captureOutput(t)
+	initial := slog.Level()
+	defer func() { _ = slog.SetLevel(initial) }()
+
+	err := errors.NewErrorToIgnore("client went away", nil)
+	_ = slog.SetLevel("info")
+	slog.Error(err, "call failed")
+	slog.NewLogger().Errorf(err, "call failed")
+	if o.String() != "" {
+		t.Errorf("expected the ignored error to be filtered out at info, got %s", o.String())
+	}
+
+	_ = slog.SetLevel("debug")
+	slog.Error(err, "call failed")
+	if line := o.String(); !strings.HasPrefix(line, "level=debug") || !strings.Contains(line, "caller=errors_test.go:") {
+		t.Errorf("expected the ignored error to be logged at debug, got %s", line)
+	}
+}
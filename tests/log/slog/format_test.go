@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/log/slog"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func setFormat(t *testing.T, name string) {
+	t.Helper()
+	initial := slog.Format()
+	if err := slog.SetFormat(name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = slog.SetFormat(initial) })
+}
+
+func setResource(t *testing.T, attributes map[string]string) {
+	initial := slog.Resource()
+	slog.SetResource(attributes)
+	t.Cleanup(func() { slog.SetResource(initial) })
+}
+
+func TestJSONFormat(t *testing.T) {
+	o := captureOutput(t)
+	setFormat(t, slog.FormatJSON)
+	setResource(t, map[string]string{"service.name": "asr", "release": "v1.2.0"})
+
+	ctx, traceID := contextWithSpan()
+	slog.WithTraceId(ctx).Warn("slow response", "duration_ms", 1200)
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(o.String()), &entry); err != nil {
+		t.Fatalf("unexpected output %q: %s", o.String(), err)
+	}
+	if entry["level"] != "warn" || entry["msg"] != "slow response" || entry["duration_ms"] != float64(1200) {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry["service.name"] != "asr" || entry["release"] != "v1.2.0" {
+		t.Errorf("expected the resource attributes, got %v", entry)
+	}
+	if entry["trace_id"] != traceID.String() || entry["span_id"] != "0400000000000000" {
+		t.Errorf("expected the trace and the span ids of the context, got %v", entry)
+	}
+}
+
+func TestSetFormat(t *testing.T) {
+	if err := slog.SetFormat("xml"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+	if slog.Format() != slog.FormatLogfmt {
+		t.Errorf("expected the format to be left as it is, got %s", slog.Format())
+	}
+}
+
+// collector is a logs collector recording the requests it receives
+type collector struct {
+	collogspb.UnimplementedLogsServiceServer
+	mutex    sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	// failures is the number of the requests to be failed before receiving them
+	failures int
+}
+
+func (c *collector) Export(_ context.Context, request *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return nil, status.Error(codes.Unavailable, "collector unavailable")
+	}
+	c.requests = append(c.requests, request)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// received waits for the lines exported periodically to be received as well, if any
+func (c *collector) received() []*collogspb.ExportLogsServiceRequest {
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		c.mutex.Lock()
+		requests := c.requests
+		c.mutex.Unlock()
+		if len(requests) > 0 || time.Now().After(deadline) {
+			return requests
+		}
+	}
+}
+
+func startCollector(t *testing.T) (*collector, *grpc.ClientConn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collector{}
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, c)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return c, conn
+}
+
+func TestOTLPFormat(t *testing.T) {
+	c, conn := startCollector(t)
+	slog.SetOTLPConn(conn)
+	setFormat(t, slog.FormatOTLP)
+	setResource(t, map[string]string{"service.name": "asr"})
+
+	ctx, traceID := contextWithSpan()
+	slog.WithTraceId(ctx).Info("call started", "call_uuid", "1", "attempt", 2)
+	if err := slog.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := c.received()
+	if len(requests) != 1 || len(requests[0].ResourceLogs) != 1 {
+		t.Fatalf("expected a request, got %v", requests)
+	}
+	resourceLogs := requests[0].ResourceLogs[0]
+	if attributes := resourceLogs.Resource.Attributes; len(attributes) != 1 ||
+		attributes[0].Key != "service.name" || attributes[0].Value.GetStringValue() != "asr" {
+		t.Errorf("expected the resource attributes, got %v", attributes)
+	}
+
+	records := resourceLogs.ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("expected a record, got %v", records)
+	}
+	record := records[0]
+	if record.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_INFO || record.SeverityText != "INFO" ||
+		record.Body.GetStringValue() != "call started" {
+		t.Errorf("unexpected record %v", record)
+	}
+	if string(record.TraceId) != string(traceID[:]) || len(record.SpanId) != 8 || record.SpanId[0] != 4 {
+		t.Errorf("expected the trace and the span ids of the context, got %v", record)
+	}
+
+	attributes := map[string]any{}
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if attributes["call_uuid"] == nil || attributes["attempt"] == nil || attributes["caller"] == nil {
+		t.Errorf("expected the fields as the attributes, got %v", record.Attributes)
+	}
+	if attributes["ts"] != nil || attributes["level"] != nil || attributes["msg"] != nil {
+		t.Errorf("expected only the fields as the attributes, got %v", record.Attributes)
+	}
+}
+
+func TestOTLPExportRetried(t *testing.T) {
+	c, conn := startCollector(t)
+	c.failures = 1
+	slog.SetOTLPConn(conn)
+	setFormat(t, slog.FormatOTLP)
+
+	slog.Info("call started", "call_uuid", "1")
+	// the first export fails, whether it is the periodic one or the flush
+	for i := 0; i < 2; i++ {
+		if err := slog.Flush(context.Background()); err == nil {
+			break
+		}
+	}
+
+	requests := c.received()
+	if len(requests) != 1 || len(requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords) != 1 {
+		t.Fatalf("expected the line to be exported once the collector is back, got %v", requests)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+func TestSetLevel(t *testing.T) {
+	initial := slog.Level()
+	defer func() { _ = slog.SetLevel(initial) }()
+
+	if err := slog.SetLevel("WARN"); err != nil || slog.Level() != "warn" {
+		t.Errorf("expected the level to be warn, got %s: %v", slog.Level(), err)
+	}
+	if err := slog.SetLevel("verbose"); err == nil || slog.Level() != "warn" {
+		t.Errorf("expected an unknown level to be rejected, got %s: %v", slog.Level(), err)
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	initial := slog.Level()
+	defer func() {
+		_ = slog.SetLevels("")
+		_ = slog.SetLevel(initial)
+	}()
+
+	if err := slog.SetLevels("transport.amqp=debug, vorm=warn,*=error"); err != nil {
+		t.Fatal(err)
+	}
+	if spec := slog.LevelsSpec(); spec != "transport.amqp=debug,vorm=warn" {
+		t.Errorf("unexpected spec %q", spec)
+	}
+	if slog.Level() != "error" {
+		t.Errorf("expected * to set the level, got %s", slog.Level())
+	}
+
+	for _, spec := range []string{"vorm=verbose", "=debug"} {
+		if err := slog.SetLevels(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+	if spec := slog.LevelsSpec(); spec != "transport.amqp=debug,vorm=warn" {
+		t.Errorf("expected an invalid spec to change nothing, got %q", spec)
+	}
+
+	if err := slog.SetLevels(""); err != nil || slog.LevelsSpec() != "" {
+		t.Errorf("expected the overrides to be removed, got %q: %v", slog.LevelsSpec(), err)
+	}
+}
+
+func TestNamedLevels(t *testing.T) {
+	o := captureOutput(t)
+	initial := slog.Level()
+	defer func() {
+		_ = slog.SetLevels("")
+		_ = slog.SetLevel(initial)
+	}()
+	if err := slog.SetLevels("kafka=debug,*=warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Named("kafka.consumer").Debug("named")
+	// a field named "logger" is not taken for the name of the logger
+	slog.Debug("unnamed", "logger", "kafka")
+	slog.Named("amqp").Info("not overridden", "logger", "kafka")
+
+	lines := o.String()
+	if !strings.Contains(lines, `logger=kafka.consumer msg=named`) {
+		t.Errorf("expected the line of the named logger to be logged at its level, got\n%s", lines)
+	}
+	if strings.Contains(lines, "unnamed") || strings.Contains(lines, "not overridden") {
+		t.Errorf("expected the fields named logger not to change the level, got\n%s", lines)
+	}
+}
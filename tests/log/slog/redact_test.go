@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	stdslog "log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+func TestRedactionPolicy(t *testing.T) {
+	slog.SetRedactionPolicy(slog.RedactionPolicy{Rules: []slog.RedactionRule{
+		{Key: "phone", Mask: slog.KeepLast(4)},
+		{Key: "PAN", Mask: slog.Hash()},
+		{Pattern: regexp.MustCompile(`(?i)transcript`)},
+	}})
+	defer slog.SetRedactionPolicy(slog.RedactionPolicy{})
+
+	var buffer bytes.Buffer
+	logger := slog.FromStdLogger(stdslog.New(stdslog.NewJSONHandler(&buffer, nil)))
+
+	logger.WithFields(map[string]any{"phone": "9876543210"}).Info("call started",
+		"pan", "ABCDE1234F",
+		"asr_transcript", "my otp is 1234",
+		"otp", slog.Secret("1234"),
+		"duration_ms", 120,
+	)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected output %q: %s", buffer.String(), err)
+	}
+	if entry["phone"] != "******3210" {
+		t.Errorf("expected the last 4 digits to be kept, got %v", entry["phone"])
+	}
+	if pan, _ := entry["pan"].(string); !strings.HasPrefix(pan, "sha256:") {
+		t.Errorf("expected the pan to be hashed, got %v", entry["pan"])
+	}
+	if entry["asr_transcript"] != slog.Redacted || entry["otp"] != slog.Redacted {
+		t.Errorf("expected the transcript and the otp to be redacted, got %v", entry)
+	}
+	if entry["duration_ms"] != float64(120) {
+		t.Errorf("expected the other fields to be logged as is, got %v", entry["duration_ms"])
+	}
+}
+
+func TestSecret(t *testing.T) {
+	if got := fmt.Sprintf("otp %d sent to %s", slog.Secret(1234), slog.Secret("9876543210")); got != "otp [REDACTED] sent to [REDACTED]" {
+		t.Errorf("expected the secrets to be redacted, got %q", got)
+	}
+}
+
+func TestParseRedactionPolicy(t *testing.T) {
+	policy, err := slog.ParseRedactionPolicy("phone=last4, otp ,pan=hash,/(?i)transcript|audio=/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Rules) != 4 {
+		t.Fatalf("expected 4 rules, got %v", policy.Rules)
+	}
+	if policy.Rules[0].Key != "phone" || policy.Rules[0].Mask("9876543210") != "******3210" {
+		t.Errorf("unexpected rule %+v", policy.Rules[0])
+	}
+	if policy.Rules[1].Key != "otp" || policy.Rules[1].Mask("1234") != slog.Redacted {
+		t.Errorf("unexpected rule %+v", policy.Rules[1])
+	}
+	if policy.Rules[3].Pattern == nil || !policy.Rules[3].Pattern.MatchString("audio=") {
+		t.Errorf("unexpected rule %+v", policy.Rules[3])
+	}
+
+	for _, spec := range []string{"phone=last", "phone=mask", "/(/", "=hash"} {
+		if _, err := slog.ParseRedactionPolicy(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
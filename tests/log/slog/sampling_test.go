@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+func setSampling(t *testing.T, name string, sampling slog.Sampling) {
+	t.Helper()
+	if err := slog.SetSampling(name, sampling); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = slog.SetSampling(name, slog.Sampling{}) })
+}
+
+func TestSamplingByMessage(t *testing.T) {
+	o := captureOutput(t)
+	setSampling(t, "*", slog.Sampling{Interval: time.Hour, First: 2, Thereafter: 3})
+
+	for i := 0; i < 10; i++ {
+		slog.Error(errors.New("timeout"), "vendor down", "attempt", i)
+	}
+	slog.Warn("vendor down")
+
+	lines := o.String()
+	for i := 0; i < 10; i++ {
+		logged := i < 2 || i == 4 || i == 7
+		if strings.Contains(lines, "attempt="+strconv.Itoa(i)+"\n") != logged {
+			t.Errorf("expected the line %d to be logged: %t, got\n%s", i, logged, lines)
+		}
+	}
+	if !strings.Contains(lines, "level=warn") {
+		t.Errorf("expected the lines of the other levels to be sampled separately, got\n%s", lines)
+	}
+}
+
+func TestSamplingLimits(t *testing.T) {
+	o := captureOutput(t)
+	setSampling(t, "transport", slog.Sampling{Limits: map[string]float64{"error": 3}})
+
+	logger := slog.Named("transport.amqp")
+	for i := 0; i < 10; i++ {
+		logger.Errorf(nil, "nack %d", i)
+		slog.Errorf(nil, "unlimited %d", i)
+	}
+
+	lines := o.String()
+	if n := strings.Count(lines, "msg=\"nack"); n != 3 {
+		t.Errorf("expected 3 lines of the named logger, got %d\n%s", n, lines)
+	}
+	if n := strings.Count(lines, "msg=\"unlimited"); n != 10 {
+		t.Errorf("expected the other loggers not to be limited, got %d\n%s", n, lines)
+	}
+}
+
+func TestSamplingSummary(t *testing.T) {
+	o := captureOutput(t)
+	var teed atomic.Bool
+	untee := slog.Tee(log.LoggerFunc(func(keyvals ...any) error {
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			if keyvals[i] == "suppressed" && keyvals[i+1] == 4 {
+				teed.Store(true)
+			}
+		}
+		return nil
+	}))
+	defer untee()
+	setSampling(t, "", slog.Sampling{Interval: 100 * time.Millisecond, First: 1})
+
+	for i := 0; i < 5; i++ {
+		slog.Info("vendor down")
+	}
+
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if strings.Contains(o.String(), "suppressed=4") && teed.Load() {
+			return
+		}
+	}
+	t.Errorf("expected a summary of the lines suppressed to be written and teed(%t), got\n%s", teed.Load(), o.String())
+}
+
+func TestSetSamplingRejectsThereafterOnly(t *testing.T) {
+	if err := slog.SetSampling("", slog.Sampling{Thereafter: 10}); err == nil {
+		t.Error("expected a sampling with only Thereafter to be rejected")
+	}
+}
+
+func TestParseSampling(t *testing.T) {
+	samplings, err := slog.ParseSampling("first=100, thereafter=10, error=50; transport.amqp:interval=5s,first=1,thereafter=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := samplings[""]; s.First != 100 || s.Thereafter != 10 || s.Limits["error"] != 50 {
+		t.Errorf("unexpected sampling %+v", s)
+	}
+	if s := samplings["transport.amqp"]; s.Interval != 5*time.Second || s.First != 1 || s.Thereafter != 0 {
+		t.Errorf("unexpected sampling %+v", s)
+	}
+
+	for _, spec := range []string{"first", "first=x", "verbose=10", "interval=1", "thereafter=10"} {
+		if _, err := slog.ParseSampling(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestSamplingCountsReset(t *testing.T) {
+	o := captureOutput(t)
+	setSampling(t, "", slog.Sampling{Interval: 50 * time.Millisecond, First: 1})
+
+	slog.Info("vendor down", "attempt", 0)
+	slog.Info("vendor down", "attempt", 1)
+	time.Sleep(60 * time.Millisecond)
+	slog.Info("vendor down", "attempt", 2)
+
+	lines := o.String()
+	if !strings.Contains(lines, "attempt=0") || strings.Contains(lines, "attempt=1") || !strings.Contains(lines, "attempt=2") {
+		t.Errorf("expected the counts of the messages to be reset once the interval is over, got\n%s", lines)
+	}
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/skit-ai/vcore/log/slog"
+)
+
+// output is a buffer safe for concurrent use capturing the lines logged
+type output struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.buffer.Write(p)
+}
+
+func (o *output) String() string {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.buffer.String()
+}
+
+func (o *output) Reset() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.buffer.Reset()
+}
+
+func captureOutput(t *testing.T) *output {
+	o := &output{}
+	slog.SetOutput(o)
+	t.Cleanup(func() {
+		slog.SetOutput(nil)
+	})
+	return o
+}
+
+// derivations are the ways a child logger can be derived from a logger
+var derivations = map[string]func(slog.Logger) slog.Logger{
+	"WithFields": func(l slog.Logger) slog.Logger {
+		return l.WithFields(map[string]any{"call_uuid": "1"})
+	},
+	"WithTraceId": func(l slog.Logger) slog.Logger {
+		return l.WithTraceId(context.Background())
+	},
+	"WithSensitive(true)": func(l slog.Logger) slog.Logger {
+		return l.WithSensitive(true)
+	},
+}
+
+// sensitiveLoggers are the ways a sensitive logger can be created
+var sensitiveLoggers = map[string]func() slog.Logger{
+	"WithSensitive": func() slog.Logger {
+		return slog.NewLogger().WithSensitive(true)
+	},
+	"SetSensitive": func() slog.Logger {
+		logger := slog.NewLogger()
+		logger.SetSensitive(true)
+		return logger
+	},
+}
+
+// logsArgs reports whether the args of every logging method of the logger are logged
+func logsArgs(t *testing.T, o *output, logger slog.Logger) (logged bool) {
+	t.Helper()
+	initial := slog.Level()
+	_ = slog.SetLevel("debug")
+	defer func() { _ = slog.SetLevel(initial) }()
+
+	o.Reset()
+	logger.Debug("msg", "transcript", "secret-debug")
+	logger.Info("msg", "transcript", "secret-info")
+	logger.Warn("msg", "transcript", "secret-warn")
+	logger.Error(nil, "msg", "transcript", "secret-error")
+	logger.Infof("msg %s", "secret-infof")
+
+	lines := o.String()
+	for _, value := range []string{"secret-debug", "secret-info", "secret-warn", "secret-error", "secret-infof"} {
+		if strings.Contains(lines, value) != strings.Contains(lines, "secret-debug") {
+			t.Errorf("expected every method to behave the same, got\n%s", lines)
+		}
+	}
+	return strings.Contains(lines, "secret-debug")
+}
+
+func TestSensitiveIsInherited(t *testing.T) {
+	o := captureOutput(t)
+
+	for base, create := range sensitiveLoggers {
+		for first, deriveFirst := range derivations {
+			for second, deriveSecond := range derivations {
+				if logsArgs(t, o, deriveSecond(deriveFirst(create()))) {
+					t.Errorf("%s.%s.%s: expected the child to be sensitive", base, first, second)
+				}
+			}
+		}
+	}
+
+	for base, create := range sensitiveLoggers {
+		if !logsArgs(t, o, create().WithSensitive(false).WithFields(map[string]any{"call_uuid": "1"})) {
+			t.Errorf("%s.WithSensitive(false): expected the child not to be sensitive", base)
+		}
+	}
+}
+
+func TestChildLoggersAreIndependent(t *testing.T) {
+	o := captureOutput(t)
+
+	parent := slog.NewLogger().WithSensitive(true)
+	child := parent.WithFields(map[string]any{"call_uuid": "1"})
+
+	parent.SetSensitive(false)
+	if logsArgs(t, o, child) {
+		t.Error("expected the child to remain sensitive when the parent changes")
+	}
+
+	child.SetSensitive(false)
+	parent.SetSensitive(true)
+	if logsArgs(t, o, parent) || !logsArgs(t, o, child) {
+		t.Error("expected the parent to be unaffected by the child")
+	}
+}
+
+func TestSetSensitiveConcurrently(t *testing.T) {
+	captureOutput(t)
+	logger := slog.NewLogger()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(sensitive bool) {
+			defer wg.Done()
+			logger.SetSensitive(sensitive)
+		}(i%2 == 0)
+		go func() {
+			defer wg.Done()
+			logger.WithFields(map[string]any{"call_uuid": "1"}).Info("msg", "transcript", "secret")
+		}()
+	}
+	wg.Wait()
+}
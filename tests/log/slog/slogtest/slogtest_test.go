@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+	"github.com/skit-ai/vcore/log/slog/slogtest"
+)
+
+// failureRecorder records the failures of the assertions instead of failing the test
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (f *failureRecorder) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func TestCapturingLogger(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.New(t)
+
+	logger.WithFields(map[string]any{"call_uuid": "1"}).Info("call ended", "turns", 3)
+	logger.Debug("debug lines are captured")
+
+	slogtest.AssertLogged(t, "info", "call ended", "call_uuid", "1", "turns", 3)
+	slogtest.AssertLogged(t, "debug", "debug lines are captured")
+	slogtest.AssertNoErrors(t)
+
+	entries := logger.Entries()
+	if len(entries) != 2 || filepath.Base(strings.Split(entries[0].Caller, ":")[0]) != "slogtest_test.go" {
+		t.Errorf("expected the caller to be the test, got %v", entries)
+	}
+}
+
+func TestAssertionFailures(t *testing.T) {
+	t.Parallel()
+	f := &failureRecorder{TB: t}
+	logger := slogtest.New(f)
+
+	logger.Error(errors.New("timeout"), "vendor down", "vendor", "asr")
+
+	slogtest.AssertLogged(f, "error", "vendor down", "vendor", "tts")
+	slogtest.AssertLogged(f, "warn", "vendor down")
+	slogtest.AssertLogged(f, "error", "vendor down", "vendor", "asr", "error", "timeout")
+	slogtest.AssertNoErrors(f)
+
+	if len(f.failures) != 3 {
+		t.Fatalf("expected 3 failures, got %q", f.failures)
+	}
+	if !strings.Contains(f.failures[0], `level=error msg="vendor down" error=timeout`) || !strings.Contains(f.failures[0], "vendor=asr") {
+		t.Errorf("expected the failure to list the lines captured, got %q", f.failures[0])
+	}
+}
+
+func TestCaptureDefault(t *testing.T) {
+	t.Parallel()
+	slogtest.CaptureDefault(t)
+
+	slog.Warn("default logger", "test", t.Name())
+	slog.Named("transport.amqp").Info("named logger", "test", t.Name())
+
+	slogtest.AssertLogged(t, "warn", "default logger", "test", t.Name())
+	slogtest.AssertLogged(t, "info", "named logger", "test", t.Name(), "logger", "transport.amqp")
+}
+
+func TestCaptureDefaultEnds(t *testing.T) {
+	var captured *slogtest.Logger
+	t.Run("capture", func(t *testing.T) {
+		captured = slogtest.CaptureDefault(t)
+	})
+
+	slog.Warn("after the test", "test", t.Name())
+	for _, entry := range captured.Entries() {
+		if entry.Msg == "after the test" {
+			t.Errorf("expected the lines logged after the test not to be captured, got %s", entry)
+		}
+	}
+}
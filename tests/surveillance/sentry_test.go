@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/surveillance"
+)
+
+// transport records the events instead of sending them
+type transport struct {
+	mutex   sync.Mutex
+	events  []*sentry.Event
+	flushes int
+	closes  int
+}
+
+func (t *transport) Configure(sentry.ClientOptions) {}
+
+func (t *transport) SendEvent(event *sentry.Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *transport) Flush(time.Duration) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.flushes++
+	return true
+}
+
+func (t *transport) Close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.closes++
+}
+
+func (t *transport) counts() (events, flushes, closes int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.events), t.flushes, t.closes
+}
+
+func newSentry(t *testing.T, opts surveillance.Options) (*surveillance.Sentry, *transport) {
+	t.Helper()
+	tr := &transport{}
+	opts.Dsn = "https://public@example.com/1"
+	opts.Transport = tr
+	client, err := surveillance.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, tr
+}
+
+func TestNew(t *testing.T) {
+	client, tr := newSentry(t, surveillance.Options{
+		Environment: "staging",
+		Release:     "v1.2.0",
+		ServerName:  "pod-1",
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			if event.Tags["drop"] == "true" {
+				return nil
+			}
+			return event
+		},
+	})
+
+	client.Capture(errors.NewError("timeout", nil, false), false)
+	client.Capture(errors.NewErrorWithTags("dropped", nil, false, map[string]string{"drop": "true"}), false)
+
+	if events, _, _ := tr.counts(); events != 1 {
+		t.Fatalf("expected 1 event to be sent, got %d", events)
+	}
+	event := tr.events[0]
+	if event.Environment != "staging" || event.Release != "v1.2.0" || event.ServerName != "pod-1" {
+		t.Errorf("expected the event to be sent with the options, got %+v", event)
+	}
+}
+
+func TestFlushAndClose(t *testing.T) {
+	client, tr := newSentry(t, surveillance.Options{})
+
+	if !client.Flush(time.Second) {
+		t.Error("expected the events to be flushed")
+	}
+	client.Close()
+	client.Close()
+
+	if _, flushes, closes := tr.counts(); flushes != 2 || closes != 1 {
+		t.Errorf("expected 2 flushes and the transport to be closed once, got %d flushes and %d closes", flushes, closes)
+	}
+}
+
+func TestNewWithoutDsn(t *testing.T) {
+	client, err := surveillance.New(surveillance.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := client.Capture(errors.NewError("timeout", nil, false), false); id != "" {
+		t.Errorf("expected the error not to be sent, got the event %s", id)
+	}
+	if !client.Flush(time.Second) {
+		t.Error("expected nothing to be flushed")
+	}
+	client.Close()
+}
+
+func TestNewWithInvalidDsn(t *testing.T) {
+	if _, err := surveillance.New(surveillance.Options{Dsn: "not a dsn"}); err == nil {
+		t.Error("expected an error for an invalid DSN")
+	}
+}
+
+func TestCaptureMulti(t *testing.T) {
+	client, tr := newSentry(t, surveillance.Options{})
+
+	var errs errors.Multi
+	errs.Append(errors.NewError("first", nil, false), errors.NewError("second", nil, false))
+	client.Capture(errs.ErrorOrNil(), false)
+
+	if events, _, _ := tr.counts(); events != 1 {
+		t.Fatalf("expected 1 event to be sent, got %d", events)
+	}
+	exceptions := tr.events[0].Exception
+	if len(exceptions) != 3 {
+		t.Fatalf("expected 3 exceptions, got %d", len(exceptions))
+	}
+
+	// the errors collected come first and the group last
+	for i, value := range []string{"first", "second"} {
+		mechanism := exceptions[i].Mechanism
+		if exceptions[i].Value != value || mechanism.ExceptionID != i || mechanism.ParentID == nil || *mechanism.ParentID != 2 {
+			t.Errorf("expected exception %d to be %q with the group as its parent, got %q with %+v", i, value, exceptions[i].Value, mechanism)
+		}
+	}
+	group := exceptions[2].Mechanism
+	if !group.IsExceptionGroup || group.ExceptionID != 2 || group.ParentID != nil {
+		t.Errorf("expected the last exception to be the group, got %+v", group)
+	}
+}
@@ -0,0 +1,87 @@
+//go:build unix
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/skit-ai/vcore/log/slog"
+	"github.com/skit-ai/vcore/surveillance"
+)
+
+// terminate sends SIGTERM to the client closing on it and waits for it to be raised again
+func terminate(t *testing.T, client *surveillance.Sentry) {
+	t.Helper()
+
+	// receiving SIGTERM here keeps the test running when the signal is raised again
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	client.CloseOnSignal(context.Background())
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-signals:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected SIGTERM to be raised again once closed, received it %d times", i)
+		}
+	}
+}
+
+func TestCloseOnSignal(t *testing.T) {
+	client, tr := newSentry(t, surveillance.Options{})
+
+	terminate(t, client)
+	if _, _, closes := tr.counts(); closes != 1 {
+		t.Errorf("expected the client to be closed, got %d closes", closes)
+	}
+}
+
+// slowWriter takes a while to write, like an output under load
+type slowWriter struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(200 * time.Millisecond)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.String()
+}
+
+func TestCloseOnSignalWritesLogs(t *testing.T) {
+	client, _ := newSentry(t, surveillance.Options{})
+	w := &slowWriter{}
+	slog.SetOutput(w)
+	slog.SetAsync(16, slog.Block)
+	t.Cleanup(func() {
+		slog.SetAsync(0, slog.Block)
+		slog.SetOutput(nil)
+	})
+
+	slog.Info("shutting down")
+	terminate(t, client)
+
+	if !strings.Contains(w.String(), "shutting down") {
+		t.Errorf("expected the lines queued to be written before SIGTERM is raised again, got %q", w.String())
+	}
+}
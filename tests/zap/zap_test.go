@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+	vzap "github.com/skit-ai/vcore/zap"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger() (*vzap.ZapLogger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return vzap.New(zap.New(core, zap.AddCaller())), logs
+}
+
+func TestLevels(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	logger.Debug("debug", "k", 1)
+	logger.Warnf("warn %d", 2)
+	logger.Error(errors.New("timeout"), "error")
+	logger.Error(errors.NewErrorToIgnore("client went away", nil), "ignored")
+	func() {
+		defer func() { _ = recover() }()
+		logger.Panic(nil, "panic")
+	}()
+
+	entries := logs.AllUntimed()
+	expected := []struct {
+		level zapcore.Level
+		msg   string
+	}{
+		{zapcore.DebugLevel, "debug"},
+		{zapcore.WarnLevel, "warn 2"},
+		{zapcore.ErrorLevel, "error"},
+		{zapcore.DebugLevel, "ignored"},
+		{zapcore.PanicLevel, "panic"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %v", len(expected), entries)
+	}
+	for i, e := range expected {
+		if entries[i].Level != e.level || entries[i].Message != e.msg {
+			t.Errorf("expected %s %q, got %s %q", e.level, e.msg, entries[i].Level, entries[i].Message)
+		}
+		if filepath.Base(entries[i].Caller.File) != "zap_test.go" {
+			t.Errorf("expected the caller to be the test, got %s", entries[i].Caller.File)
+		}
+	}
+	if entries[0].ContextMap()["k"] != int64(1) || entries[2].ContextMap()["error"] != "timeout" {
+		t.Errorf("unexpected fields %v, %v", entries[0].ContextMap(), entries[2].ContextMap())
+	}
+}
+
+func TestLegacyMapArgs(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	logger.Info("call started", map[string]interface{}{"call_uuid": "1"}, map[string]interface{}{"tid": "t1"})
+
+	message, _ := logs.AllUntimed()[0].ContextMap()["message"].(map[string]interface{})
+	payload, _ := message["payload"].(map[string]interface{})
+	if payload["call_uuid"] != "1" || message["tid"] != "t1" || message["level"] != "INFO" || message["event"] != "log" {
+		t.Errorf("expected the message of the maps, got %v", message)
+	}
+}
+
+func TestDerivedLoggers(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	var l slog.Logger = logger.With("service", "asr")
+	l = l.WithFields(map[string]any{"call_uuid": "1"})
+
+	traceID := trace.TraceID{1}
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{2}}))
+	l.InfoContext(ctx, "traced", "turn", 3)
+	l.WithSensitive(true).Info("transcript", "text", "secret")
+
+	entries := logs.AllUntimed()
+	fields := entries[0].ContextMap()
+	if fields["service"] != "asr" || fields["call_uuid"] != "1" || fields["turn"] != int64(3) || fields["trace_id"] == nil {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	if fields := entries[1].ContextMap(); fields["text"] != nil || fields["call_uuid"] != "1" {
+		t.Errorf("expected the args to be left out, got %v", fields)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("ZAP_LEVEL", "warn")
+	t.Setenv("ZAP_ENCODING", "json")
+	t.Setenv("ZAP_OUTPUT_PATHS", filepath.Join(t.TempDir(), "zap.log"))
+	t.Setenv("ZAP_SAMPLING", "10,5")
+	if _, err := vzap.NewFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range map[string]string{
+		"ZAP_LEVEL":    "verbose",
+		"ZAP_ENCODING": `json", "level": "info`,
+		"ZAP_SAMPLING": "10",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := vzap.NewFromEnv(); err == nil {
+				t.Errorf("expected an error for %s=%s", name, value)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package amqp
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/skit-ai/vcore/errors"
 	"github.com/streadway/amqp"
 )
 
@@ -30,7 +32,10 @@ func NewConsumer(amqpURI, exchange, exchangeType, queueName, ctag string, keys [
 	var err error
 
 	log.Printf("dialing %q", amqpURI)
-	c.conn, err = amqp.Dial(amqpURI)
+	err = errors.Retry(context.Background(), errors.NetworkRetryPolicy, func(context.Context) (err error) {
+		c.conn, err = amqp.Dial(amqpURI)
+		return
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Dial: %s", err)
 	}
@@ -1,9 +1,11 @@
 package amqp
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/skit-ai/vcore/errors"
 	"github.com/streadway/amqp"
 )
 
@@ -26,7 +28,10 @@ func NewProducer(amqpURI, exchange, exchangeType string) (*Producer, error) {
 	var err error
 
 	log.Printf("dialing %q", amqpURI)
-	producer.conn, err = amqp.Dial(amqpURI)
+	err = errors.Retry(context.Background(), errors.NetworkRetryPolicy, func(context.Context) (err error) {
+		producer.conn, err = amqp.Dial(amqpURI)
+		return
+	})
 	if err != nil {
 		log.Printf("Dial: %s", err)
 		return nil, err
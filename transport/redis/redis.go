@@ -1,10 +1,12 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/mediocregopher/radix.v2/redis"
+	"github.com/skit-ai/vcore/errors"
 )
 
 var (
@@ -19,7 +21,11 @@ type RadixRedisClient struct {
 
 // NewRadixRedisClient - Return a redis client
 func NewRadixRedisClient() (*RadixRedisClient, error) {
-	redisClient, err := redis.Dial("tcp", fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")))
+	var redisClient *redis.Client
+	err := errors.Retry(context.Background(), errors.NetworkRetryPolicy, func(context.Context) (err error) {
+		redisClient, err = redis.Dial("tcp", fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")))
+		return
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,10 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"github.com/mediocregopher/radix/v3"
+	"github.com/skit-ai/vcore/errors"
 	//"github.com/mediocregopher/radix.v2/redis"
 	"os"
 )
@@ -20,25 +22,26 @@ type RadixRedisClient struct {
 
 // NewRadixRedisClient - Return a redis client
 func NewRadixRedisClient() (*RadixRedisClient, error) {
-	redisClient, err := radix.NewPool("tcp", fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")), 10)
-	if err != nil {
-		return nil, err
-	}
-	return &RadixRedisClient{redisClient}, nil
+	return newPool(fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")), 10)
 }
 
 // NewRadixRedisClient - Return a redis client
 func NewRadixRedisPool(size int, opts ...radix.PoolOpt) (*RadixRedisClient, error) {
-	redisClient, err := radix.NewPool("tcp", fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")), size, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return &RadixRedisClient{redisClient}, nil
+	return newPool(fmt.Sprintf("%v:%v", os.Getenv("REDIS_REMOTE_HOST"), os.Getenv("REDIS_REMOTE_PORT")), size, opts...)
 }
 
 // NewRadixRedisClient - Return a redis client
 func NewRadixRedisClientUsingCustomHostPort(host, port string) (*RadixRedisClient, error) {
-	redisClient, err := radix.NewPool("tcp", fmt.Sprintf("%v:%v", host, port), 10)
+	return newPool(fmt.Sprintf("%v:%v", host, port), 10)
+}
+
+// newPool creates a pool of connections to redis, retrying as per errors.NetworkRetryPolicy
+func newPool(addr string, size int, opts ...radix.PoolOpt) (*RadixRedisClient, error) {
+	var redisClient *radix.Pool
+	err := errors.Retry(context.Background(), errors.NetworkRetryPolicy, func(context.Context) (err error) {
+		redisClient, err = radix.NewPool("tcp", addr, size, opts...)
+		return
+	})
 	if err != nil {
 		return nil, err
 	}
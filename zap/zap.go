@@ -2,80 +2,338 @@
 package zap
 
 import (
-	"go.uber.org/zap"
-	// "log"
-	"encoding/json"
+	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/skit-ai/vcore/env"
+	"github.com/skit-ai/vcore/errors"
+	"github.com/skit-ai/vcore/log/slog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+// Logger is the logger configured using the envs(see NewFromEnv)
 var Logger *ZapLogger
 
+// ZapLogger is a leveled structured logger logging using zap. It implements
+// slog.Logger so that it can be used wherever a slog.Logger is expected.
 type ZapLogger struct {
 	logger *zap.Logger
+	// sugar logs the args as loosely typed key value pairs, skipping the frames of the ZapLogger for the caller
+	sugar     *zap.SugaredLogger
+	sensitive atomic.Bool // if sensitive is true, dont log the values
 }
 
-// func New() *ZapLogger{
-//   logger, _ := zap.NewProduction()
-//   return &ZapLogger{"logger": logger}
-// }
-
-// func init(){
-//   logger, _ := zap.NewProduction()
-//   Logger = &ZapLogger{logger: logger}
-// }
+var _ slog.Logger = (*ZapLogger)(nil)
 
 func init() {
-	var encoding_type string
-	var cfg zap.Config
-
-	app_env := os.Getenv("APP_ENV")
-	//source := os.Getenv("APP_NAME")
-	if app_env == "production" {
-		encoding_type = "json"
-	} else {
-		encoding_type = "console"
-	}
-	rawJSON := []byte(`{
-      "level": "debug",
-      "encoding": "` + encoding_type + `",
-      "outputPaths": ["stdout"],
-      "errorOutputPaths": ["stderr"],
-      "encoderConfig": {
-        "messageKey": "log",
-        "levelKey": "level",
-        "levelEncoder": "uppercase"
-      }
-    }`)
-
-	if err := json.Unmarshal(rawJSON, &cfg); err != nil {
-		panic(err)
+	cfg, err := configFromEnv()
+	logger, buildErr := cfg.Build()
+	if buildErr != nil {
+		// for eg. an output path which can not be opened
+		err = errors.Join(err, buildErr)
+		logger, _ = defaultConfig().Build()
+	}
+	Logger = New(logger)
+	// Invalid envs are ignored, like the ones of log/slog, so that importing the package never fails
+	if err != nil {
+		Logger.Warn("ignoring the invalid zap envs", "error", err.Error())
+	}
+}
+
+// New returns a ZapLogger logging using the zap logger
+func New(logger *zap.Logger) *ZapLogger {
+	return &ZapLogger{logger: logger, sugar: logger.WithOptions(zap.AddCallerSkip(2)).Sugar()}
+}
+
+// NewFromEnv returns a ZapLogger configured using the envs:
+//   - ZAP_LEVEL, "debug" by default
+//   - ZAP_ENCODING, "json" or "console". By default "json" if APP_ENV is "production", "console" otherwise.
+//   - ZAP_OUTPUT_PATHS and ZAP_ERROR_OUTPUT_PATHS, comma separated lists of
+//     paths or URLs(see zap.Open), "stdout" and "stderr" by default
+//   - ZAP_SAMPLING, "<first>,<thereafter>" to log the first lines of each
+//     message every second and then every thereafter-th one. No sampling by default.
+//
+// An error is returned if any of the envs is invalid.
+func NewFromEnv() (*ZapLogger, error) {
+	cfg, err := configFromEnv()
+	if err != nil {
+		return nil, err
 	}
+
 	logger, err := cfg.Build()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	return New(logger), nil
+}
+
+// defaultConfig returns the config logging at debug to stdout, in the json encoding if APP_ENV is "production" and
+// in the console encoding otherwise
+func defaultConfig() zap.Config {
+	encoding := "console"
+	if os.Getenv("APP_ENV") == "production" {
+		encoding = "json"
+	}
+
+	return zap.Config{
+		Level:    zap.NewAtomicLevelAt(zap.DebugLevel),
+		Encoding: encoding,
+		EncoderConfig: zapcore.EncoderConfig{
+			MessageKey:  "log",
+			LevelKey:    "level",
+			EncodeLevel: zapcore.CapitalLevelEncoder,
+		},
+		OutputPaths:      []string{"stdout"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+}
+
+// configFromEnv returns the config set using the envs(see NewFromEnv). The
+// invalid envs are left as the defaults and the errors for them are returned along with the config.
+func configFromEnv() (zap.Config, error) {
+	cfg := defaultConfig()
+	var errs []error
+
+	if name := env.String("ZAP_LEVEL", ""); name != "" {
+		if level, err := zap.ParseAtomicLevel(name); err != nil {
+			errs = append(errs, fmt.Errorf("invalid ZAP_LEVEL: %w", err))
+		} else {
+			cfg.Level = level
+		}
+	}
+
+	switch encoding := env.String("ZAP_ENCODING", ""); encoding {
+	case "":
+	case "json", "console":
+		cfg.Encoding = encoding
+	default:
+		errs = append(errs, fmt.Errorf("invalid ZAP_ENCODING %q, expected json or console", encoding))
+	}
+
+	if outputs := paths(env.String("ZAP_OUTPUT_PATHS", "")); len(outputs) > 0 {
+		cfg.OutputPaths = outputs
+	}
+	if outputs := paths(env.String("ZAP_ERROR_OUTPUT_PATHS", "")); len(outputs) > 0 {
+		cfg.ErrorOutputPaths = outputs
+	}
+
+	if sampling := env.String("ZAP_SAMPLING", ""); sampling != "" {
+		first, thereafter, found := strings.Cut(sampling, ",")
+		initial, initialErr := strconv.Atoi(strings.TrimSpace(first))
+		every, everyErr := strconv.Atoi(strings.TrimSpace(thereafter))
+		if !found || initialErr != nil || everyErr != nil {
+			errs = append(errs, fmt.Errorf("invalid ZAP_SAMPLING %q, expected <first>,<thereafter>", sampling))
+		} else {
+			cfg.Sampling = &zap.SamplingConfig{Initial: initial, Thereafter: every}
+		}
 	}
-	//defer Logger.Sync()
-	Logger = &ZapLogger{logger: logger}
+
+	return cfg, errors.Join(errs...)
+}
+
+// paths splits a comma separated list of paths
+func paths(list string) []string {
+	var paths []string
+	for _, path := range strings.Split(list, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
 }
 
-// Proxy for reflect method
-// func Reflect(key string, val interface{}) zap.Field{
-//   return zap.Reflect(key, val)
-// }
+// derive returns a child of the logger logging using the given zap logger, inheriting the sensitive flag
+func (zaplogger *ZapLogger) derive(logger *zap.Logger) *ZapLogger {
+	child := New(logger)
+	child.sensitive.Store(zaplogger.sensitive.Load())
+	return child
+}
 
-func (zaplogger *ZapLogger) Info(log string, args ...map[string]interface{}) {
-	// Build the log metadata
-	var log_message map[string]interface{} = map[string]interface{}{}
-	if len(args) > 1 {
-		log_message = args[1]
+// isLegacy reports whether the args are the maps passed to Info before it took key value pairs
+func isLegacy(args []any) bool {
+	if len(args) == 0 || len(args) > 2 {
+		return false
+	}
+	for _, arg := range args {
+		if _, ok := arg.(map[string]interface{}); !ok {
+			return false
+		}
 	}
-	if len(args) > 0 {
+	return true
+}
+
+// write logs a line at the level along with the error, if not nil, and the args
+// as key value pairs. The args can also be up to two maps, the first one logged
+// as the "payload" of the "message" and the second one merged into the
+// "message", like Info did before it took key value pairs. The errors to be
+// ignored(see errors.Ignore) are logged at debug instead of error.
+func (zaplogger *ZapLogger) write(level zapcore.Level, err error, msg string, args []any) {
+	if zaplogger.sensitive.Load() {
+		args = nil
+	}
+
+	if isLegacy(args) {
+		var log_message map[string]interface{} = map[string]interface{}{}
+		if len(args) > 1 {
+			log_message = args[1].(map[string]interface{})
+		}
 		log_message["payload"] = args[0]
+		log_message["level"] = level.CapitalString()
+		log_message["event"] = "log"
+		args = []any{zap.Reflect("message", log_message)}
+	}
+
+	if err != nil {
+		if level == zapcore.ErrorLevel && errors.Ignore(err) {
+			level = zapcore.DebugLevel
+		}
+		args = append([]any{zap.Error(err)}, args...)
+	}
+
+	switch level {
+	case zapcore.DebugLevel:
+		zaplogger.sugar.Debugw(msg, args...)
+	case zapcore.InfoLevel:
+		zaplogger.sugar.Infow(msg, args...)
+	case zapcore.WarnLevel:
+		zaplogger.sugar.Warnw(msg, args...)
+	case zapcore.ErrorLevel:
+		zaplogger.sugar.Errorw(msg, args...)
+	case zapcore.PanicLevel:
+		zaplogger.sugar.Panicw(msg, args...)
+	case zapcore.FatalLevel:
+		zaplogger.sugar.Fatalw(msg, args...)
+	}
+}
+
+// sprintf formats the message, leaving out the args if the logger is sensitive
+func (zaplogger *ZapLogger) sprintf(format string, args []any) string {
+	if zaplogger.sensitive.Load() {
+		args = nil
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Info logs a line with level info.
+func (zaplogger *ZapLogger) Info(msg string, args ...any) {
+	zaplogger.write(zapcore.InfoLevel, nil, msg, args)
+}
+
+// Warn logs a line with level warn.
+func (zaplogger *ZapLogger) Warn(msg string, args ...any) {
+	zaplogger.write(zapcore.WarnLevel, nil, msg, args)
+}
+
+// Debug logs a line with level debug.
+func (zaplogger *ZapLogger) Debug(msg string, args ...any) {
+	zaplogger.write(zapcore.DebugLevel, nil, msg, args)
+}
+
+// Error logs a line with level error along with the error, if not nil.
+func (zaplogger *ZapLogger) Error(err error, msg string, args ...any) {
+	zaplogger.write(zapcore.ErrorLevel, err, msg, args)
+}
+
+// Fatal logs a line with level fatal along with the error, if not nil, and exits.
+func (zaplogger *ZapLogger) Fatal(err error, msg string, args ...any) {
+	zaplogger.write(zapcore.FatalLevel, err, msg, args)
+}
+
+// Panic logs a line with level panic along with the error, if not nil, and panics.
+func (zaplogger *ZapLogger) Panic(err error, msg string, args ...any) {
+	zaplogger.write(zapcore.PanicLevel, err, msg, args)
+}
+
+// Infof logs a format line with level info.
+func (zaplogger *ZapLogger) Infof(format string, args ...any) {
+	zaplogger.write(zapcore.InfoLevel, nil, zaplogger.sprintf(format, args), nil)
+}
+
+// Warnf logs a format line with level warn.
+func (zaplogger *ZapLogger) Warnf(format string, args ...any) {
+	zaplogger.write(zapcore.WarnLevel, nil, zaplogger.sprintf(format, args), nil)
+}
+
+// Debugf logs a format line with level debug.
+func (zaplogger *ZapLogger) Debugf(format string, args ...any) {
+	zaplogger.write(zapcore.DebugLevel, nil, zaplogger.sprintf(format, args), nil)
+}
+
+// Errorf logs a format line with level error along with the error, if not nil.
+func (zaplogger *ZapLogger) Errorf(err error, format string, args ...any) {
+	zaplogger.write(zapcore.ErrorLevel, err, zaplogger.sprintf(format, args), nil)
+}
+
+// InfoContext logs a line with level info along with the fields of the context(see slog.FieldsFromContext).
+func (zaplogger *ZapLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	zaplogger.withContext(ctx).write(zapcore.InfoLevel, nil, msg, args)
+}
+
+// WarnContext logs a line with level warn along with the fields of the context(see slog.FieldsFromContext).
+func (zaplogger *ZapLogger) WarnContext(ctx context.Context, msg string, args ...any) {
+	zaplogger.withContext(ctx).write(zapcore.WarnLevel, nil, msg, args)
+}
+
+// DebugContext logs a line with level debug along with the fields of the context(see slog.FieldsFromContext).
+func (zaplogger *ZapLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	zaplogger.withContext(ctx).write(zapcore.DebugLevel, nil, msg, args)
+}
+
+// ErrorContext logs a line with level error along with the error, if not nil, and the fields of the context.
+func (zaplogger *ZapLogger) ErrorContext(ctx context.Context, err error, msg string, args ...any) {
+	zaplogger.withContext(ctx).write(zapcore.ErrorLevel, err, msg, args)
+}
+
+// withContext returns a child of the logger with the fields of the context attached
+func (zaplogger *ZapLogger) withContext(ctx context.Context) *ZapLogger {
+	fields := slog.FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return zaplogger
 	}
-	log_message["level"] = "INFO"
-	log_message["event"] = "log"
-	// tid, event, timestamp
-	zaplogger.logger.Info(log, zap.Reflect("message", log_message))
+	return zaplogger.withFields(fields)
 }
 
-// Debug, Warn, Error, Fatal, Panic
+// WithTraceId returns a child of the logger with the fields of the context attached, like slog.WithTraceId.
+func (zaplogger *ZapLogger) WithTraceId(ctx context.Context) slog.Logger {
+	return zaplogger.withContext(ctx)
+}
+
+// WithFields returns a child of the logger with the fields attached.
+func (zaplogger *ZapLogger) WithFields(fields map[string]any) slog.Logger {
+	return zaplogger.withFields(fields)
+}
+
+func (zaplogger *ZapLogger) withFields(fields map[string]any) *ZapLogger {
+	zapFields := make([]zap.Field, 0, len(fields))
+	for k, v := range fields {
+		zapFields = append(zapFields, zap.Any(k, v))
+	}
+	return zaplogger.derive(zaplogger.logger.With(zapFields...))
+}
+
+// With returns a child of the logger with the args, key value pairs or zap fields, attached.
+func (zaplogger *ZapLogger) With(args ...any) *ZapLogger {
+	return zaplogger.derive(zaplogger.logger.Sugar().With(args...).Desugar())
+}
+
+// WithSensitive returns a child of the logger with the sensitive flag set, leaving out the args of the lines.
+func (zaplogger *ZapLogger) WithSensitive(sensitive bool) slog.Logger {
+	child := zaplogger.derive(zaplogger.logger)
+	child.sensitive.Store(sensitive)
+	return child
+}
+
+// SetSensitive changes the sensitive flag of the logger. The loggers derived from it earlier are not affected.
+func (zaplogger *ZapLogger) SetSensitive(val bool) {
+	zaplogger.sensitive.Store(val)
+}
+
+// Sync flushes the lines buffered, if any. It is meant to be called when the service shuts down.
+func (zaplogger *ZapLogger) Sync() error {
+	return zaplogger.logger.Sync()
+}